@@ -4,16 +4,113 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
+	"sync"
 	"time"
 )
 
-const HTTPTimeout = time.Second * 90
+const (
+	DefaultHTTPTimeout = time.Second * 90
+
+	ClockSkewProbeTimeout = time.Second * 10
+	MaxClockSkew          = time.Minute * 5
+)
+
+var (
+	// HTTPTimeout bounds each request to the Trident REST interface, or is zero for no timeout
+	HTTPTimeout = DefaultHTTPTimeout
+
+	// RequestContext, once done, cancels any request in flight and fails any later ones
+	RequestContext = context.Background()
+
+	lastRequest      string
+	lastRequestMutex sync.Mutex
+
+	// BearerToken, if set, authenticates requests to the Trident REST interface
+	BearerToken string
+
+	tlsConfig *tls.Config
+
+	// DebugOutput receives the requests and responses logged with debug, keeping them out of the results
+	DebugOutput io.Writer = os.Stderr
+
+	// DryRun, if set, prints each request to the Trident REST interface instead of sending it
+	DryRun bool
+
+	// ErrDryRun is returned in place of a response to a request that was not sent because of DryRun
+	ErrDryRun = errors.New("dry run; the request was not sent")
+)
+
+// SetCertificateAuthority configures the client to trust servers whose certificates are signed by
+// the certificate authority in the specified PEM file.
+func SetCertificateAuthority(caFile string) error {
+
+	caBytes, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("could not read certificate authority %s; %v", caFile, err)
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caBytes) {
+		return fmt.Errorf("no certificates found in certificate authority %s", caFile)
+	}
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.RootCAs = rootCAs
+	return nil
+}
+
+// SetInsecureSkipVerify configures the client to accept any server certificate.  This makes HTTPS
+// connections vulnerable to interception, so it is only meant for testing.
+func SetInsecureSkipVerify() {
+
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.InsecureSkipVerify = true
+}
+
+// newHTTPClient returns a client honoring the configured TLS settings and any proxy in the environment.
+func newHTTPClient() *http.Client {
+
+	client := &http.Client{Timeout: HTTPTimeout}
+	if tlsConfig != nil {
+		client.Transport = &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig}
+	}
+	return client
+}
+
+// LastRequest returns the method and URL of the most recent REST request, for reporting failures.
+func LastRequest() string {
+	lastRequestMutex.Lock()
+	defer lastRequestMutex.Unlock()
+	return lastRequest
+}
 
 func InvokeRESTAPI(method string, url string, requestBody []byte, debug bool) (*http.Response, []byte, error) {
+	return invokeHTTP(method, url, requestBody, debug, true)
+}
+
+// Fetch reads a URL with the same TLS and proxy settings as the REST client, but without sending
+// the REST credentials, which are meant only for Trident.
+func Fetch(url string, debug bool) (*http.Response, []byte, error) {
+	return invokeHTTP("GET", url, nil, debug, false)
+}
+
+func invokeHTTP(
+	method string, url string, requestBody []byte, debug, authenticate bool,
+) (*http.Response, []byte, error) {
 
 	var request *http.Request
 	var err error
@@ -26,18 +123,36 @@ func InvokeRESTAPI(method string, url string, requestBody []byte, debug bool) (*
 	if err != nil {
 		return nil, nil, err
 	}
+	request = request.WithContext(RequestContext)
 
 	request.Header.Set("Content-Type", "application/json")
+	if authenticate && BearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+BearerToken)
+	}
+
+	lastRequestMutex.Lock()
+	lastRequest = method + " " + url
+	lastRequestMutex.Unlock()
+
+	if DryRun && authenticate {
+		fmt.Fprintf(os.Stdout, "%s %s\n", method, url)
+		if len(requestBody) > 0 {
+			fmt.Fprintf(os.Stdout, "%s\n", string(requestBody))
+		}
+		return nil, nil, ErrDryRun
+	}
 
 	if debug {
 		LogHTTPRequest(request, requestBody)
 	}
 
-	client := &http.Client{Timeout: HTTPTimeout}
+	client := newHTTPClient()
 	response, err := client.Do(request)
 
 	responseBody := []byte{}
-	if err == nil {
+	if err != nil {
+		err = checkClockSkew(request, err)
+	} else {
 
 		responseBody, err = ioutil.ReadAll(response.Body)
 		response.Body.Close()
@@ -50,24 +165,76 @@ func InvokeRESTAPI(method string, url string, requestBody []byte, debug bool) (*
 	return response, responseBody, err
 }
 
+// checkClockSkew adds a hint to certificate validity-period failures if the local clock appears to be
+// skewed relative to the server's, since the resulting x509 errors are otherwise hard to interpret.
+func checkClockSkew(request *http.Request, err error) error {
+
+	cause := err
+	if urlErr, ok := err.(*url.Error); ok {
+		cause = urlErr.Err
+	}
+	if certErr, ok := cause.(x509.CertificateInvalidError); !ok || certErr.Reason != x509.Expired {
+		return err
+	}
+
+	// The certificate can't be trusted, so only read the server's Date header without sending anything
+	probeRequest, probeErr := http.NewRequest("HEAD", request.URL.String(), nil)
+	if probeErr != nil {
+		return err
+	}
+	probeClient := &http.Client{
+		Timeout:   ClockSkewProbeTimeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	probeResponse, probeErr := probeClient.Do(probeRequest)
+	if probeErr != nil {
+		return err
+	}
+	probeResponse.Body.Close()
+
+	serverTime, probeErr := http.ParseTime(probeResponse.Header.Get("Date"))
+	if probeErr != nil {
+		return err
+	}
+
+	skew := time.Since(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew < MaxClockSkew {
+		return err
+	}
+
+	return fmt.Errorf("%v (possible clock skew: the local clock differs from the server's by %v)",
+		err, skew.Round(time.Second))
+}
+
 func LogHTTPRequest(request *http.Request, requestBody []byte) {
-	fmt.Fprint(os.Stdout, "--------------------------------------------------------------------------------\n")
-	fmt.Fprintf(os.Stdout, "Request Method: %s\n", request.Method)
-	fmt.Fprintf(os.Stdout, "Request URL: %v\n", request.URL)
-	fmt.Fprintf(os.Stdout, "Request headers: %v\n", request.Header)
+	fmt.Fprint(DebugOutput, "--------------------------------------------------------------------------------\n")
+	fmt.Fprintf(DebugOutput, "Request Method: %s\n", request.Method)
+	fmt.Fprintf(DebugOutput, "Request URL: %v\n", request.URL)
+	headers := request.Header
+	if headers.Get("Authorization") != "" {
+		headers = make(http.Header)
+		for name, values := range request.Header {
+			headers[name] = values
+		}
+		headers.Set("Authorization", "<REDACTED>")
+	}
+	fmt.Fprintf(DebugOutput, "Request headers: %v\n", headers)
 	if requestBody == nil {
 		requestBody = []byte{}
 	}
-	fmt.Fprintf(os.Stdout, "Request body: %s\n", string(requestBody))
-	fmt.Fprint(os.Stdout, "................................................................................\n")
+	fmt.Fprintf(DebugOutput, "Request body: %s\n", string(requestBody))
+	fmt.Fprint(DebugOutput, "................................................................................\n")
 }
 
 func LogHTTPResponse(response *http.Response, responseBody []byte) {
-	fmt.Fprintf(os.Stdout, "Response status: %s\n", response.Status)
-	fmt.Fprintf(os.Stdout, "Response headers: %v\n", response.Header)
+	fmt.Fprintf(DebugOutput, "Response status: %s\n", response.Status)
+	fmt.Fprintf(DebugOutput, "Response headers: %v\n", response.Header)
 	if responseBody == nil {
 		responseBody = []byte{}
 	}
-	fmt.Fprintf(os.Stdout, "Response body: %s\n", string(responseBody))
-	fmt.Fprint(os.Stdout, "================================================================================\n")
+	fmt.Fprintf(DebugOutput, "Response body: %s\n", string(responseBody))
+	fmt.Fprint(DebugOutput, "================================================================================\n")
 }
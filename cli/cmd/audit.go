@@ -0,0 +1,180 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var AuditLog string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&AuditLog, "audit-log", "",
+		"Append each command and its result to this tamper-evident, hash-chained log file")
+
+	RootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:         "audit",
+	Short:       "Manage tridentctl audit logs",
+	Annotations: map[string]string{annotationSkipDiscovery: "true"},
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify <file>",
+	Short: "Verify the integrity of a tridentctl audit log",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := verifyAuditLog(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Verified %d audit log entries in %s.\n", count, args[0])
+		return nil
+	},
+}
+
+// AuditEntry records one invocation of tridentctl.  Each entry's hash covers its contents and the
+// hash of the previous entry, so altering or removing any entry breaks the chain after it.
+type AuditEntry struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	Args         []string        `json:"args"`
+	ExitCode     int             `json:"exitCode"`
+	Result       json.RawMessage `json:"result"`
+	PreviousHash string          `json:"previousHash"`
+	Hash         string          `json:"hash"`
+}
+
+// computeHash returns the hash of an entry's contents, excluding its own hash.
+func (e AuditEntry) computeHash() (string, error) {
+
+	e.Hash = ""
+	entryBytes, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(entryBytes)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// writeAuditEntry appends the command and its result to the audit log, if one was requested.
+func writeAuditEntry(exitCode int, result []byte) {
+
+	if AuditLog == "" {
+		return
+	}
+
+	if err := appendAuditEntry(os.Args, exitCode, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write to the audit log %s; %v\n", AuditLog, err)
+	}
+}
+
+// canonicalizeResult returns a JSON result in compact form with sorted keys, so that equivalent
+// results hash identically, or any other result as a JSON string.
+func canonicalizeResult(result []byte) (json.RawMessage, error) {
+
+	var value interface{}
+	if err := json.Unmarshal(result, &value); err != nil {
+		value = string(result)
+	}
+
+	return json.Marshal(value)
+}
+
+func appendAuditEntry(args []string, exitCode int, result []byte) error {
+
+	previousHash, _, err := readAuditChain(AuditLog)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	canonicalResult, err := canonicalizeResult(result)
+	if err != nil {
+		return err
+	}
+
+	entry := AuditEntry{
+		Timestamp:    time.Now().UTC(),
+		Args:         args,
+		ExitCode:     exitCode,
+		Result:       canonicalResult,
+		PreviousHash: previousHash,
+	}
+	if entry.Hash, err = entry.computeHash(); err != nil {
+		return err
+	}
+
+	entryBytes, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	auditFile, err := os.OpenFile(AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer auditFile.Close()
+
+	_, err = auditFile.Write(append(entryBytes, '\n'))
+	return err
+}
+
+// readAuditChain verifies an audit log, returning the hash of its last entry and its entry count.
+func readAuditChain(path string) (string, int, error) {
+
+	auditFile, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer auditFile.Close()
+
+	previousHash := ""
+	count := 0
+
+	scanner := bufio.NewScanner(auditFile)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+
+		count++
+
+		var entry AuditEntry
+		if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return "", count, fmt.Errorf("audit log entry %d is invalid; %v", count, err)
+		}
+
+		if entry.PreviousHash != previousHash {
+			return "", count, fmt.Errorf("audit log entry %d does not follow the previous entry", count)
+		}
+
+		hash, err := entry.computeHash()
+		if err != nil {
+			return "", count, err
+		} else if hash != entry.Hash {
+			return "", count, fmt.Errorf("audit log entry %d has been modified", count)
+		}
+
+		previousHash = entry.Hash
+	}
+	if err = scanner.Err(); err != nil {
+		return "", count, err
+	}
+
+	return previousHash, count, nil
+}
+
+// verifyAuditLog checks the hash chain of an audit log, returning the number of entries verified.
+func verifyAuditLog(path string) (int, error) {
+	_, count, err := readAuditChain(path)
+	return count, err
+}
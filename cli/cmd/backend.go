@@ -0,0 +1,15 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(backendCmd)
+}
+
+var backendCmd = &cobra.Command{
+	Use:         "backend",
+	Short:       "Manage the operation of a backend in Trident",
+	Annotations: map[string]string{annotationNamespacedArgs: "true"},
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/storage"
+)
+
+var rotateCheckOnly bool
+
+func init() {
+	backendCmd.AddCommand(backendRotateCredentialsCmd)
+	backendRotateCredentialsCmd.Flags().StringVarP(&filename, "filename", "f", "",
+		"Path or http, https, or file URL of YAML or JSON backend file containing the new credentials")
+	backendRotateCredentialsCmd.Flags().StringVarP(&b64Data, "base64", "", "", "Base64 encoding")
+	backendRotateCredentialsCmd.Flags().MarkHidden("base64")
+	backendRotateCredentialsCmd.Flags().BoolVar(&rotateCheckOnly, "check-only", false,
+		"Only check that the backend file matches the backend, without testing or applying the new credentials")
+}
+
+var backendRotateCredentialsCmd = &cobra.Command{
+	Use:         "rotate-credentials <name>",
+	Short:       "Replace the credentials of a backend and verify that Trident can still reach it",
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationREST: "POST /backend/{name}"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		jsonData, err := getBackendData()
+		if err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{
+				"backend", "rotate-credentials",
+				"--base64", base64.StdEncoding.EncodeToString(jsonData),
+			}
+			var podFlags []podFlag
+			if rotateCheckOnly {
+				podFlags = append(podFlags, requiredPodFlag("--check-only"))
+			}
+			TunnelCommand(append(command, args...), podFlags...)
+			return nil
+		} else {
+			return backendRotateCredentials(args[0], jsonData)
+		}
+	},
+}
+
+// backendRotateCredentials updates a backend with a backend file containing new credentials.  Trident
+// reinitializes a backend when it is updated, so the update fails if the credentials don't work, and
+// the backend must be online afterward.
+func backendRotateCredentials(backendName string, postData []byte) error {
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	backend, err := GetBackend(baseURL, backendName)
+	if err != nil {
+		return err
+	}
+
+	if err = validateRotatedBackend(backend, postData); err != nil {
+		return err
+	}
+
+	// The credentials can only be tested by applying them, so checking only compares the backend file
+	if rotateCheckOnly {
+		fmt.Printf("The backend file matches backend %s; its credentials were not tested, and nothing "+
+			"was changed.\n", backendName)
+		return nil
+	}
+
+	url := baseURL + "/backend/" + backendName
+	refetch := func() (err error) {
+		_, err = GetBackend(baseURL, backendName)
+		return err
+	}
+
+	response, responseBody, err := invokeUpdateRESTAPI("POST", url, postData, refetch)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not rotate the credentials of backend %s: %v", backendName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	// Re-validate the backend with its new credentials
+	if backend, err = GetBackend(baseURL, backendName); err != nil {
+		return err
+	}
+	if backend.State != storage.Online {
+		return fmt.Errorf("backend %s is %s after rotating its credentials", backendName, backend.State)
+	}
+
+	fmt.Printf("Rotated the credentials of backend %s, which is online.\n", backendName)
+
+	return nil
+}
+
+// validateRotatedBackend checks that a backend file describes the same backend, so that rotating the
+// credentials can't inadvertently replace the backend with another one.
+func validateRotatedBackend(backend storage.BackendExternal, postData []byte) error {
+
+	var newConfig map[string]interface{}
+	if err := json.Unmarshal(postData, &newConfig); err != nil {
+		return err
+	}
+
+	newDriver, err := getFieldValue(newConfig, "storageDriverName")
+	if err != nil {
+		return errors.New("the backend file does not specify a storage driver")
+	}
+	driver, err := getFieldValue(backend.Config, "storageDriverName")
+	if err == nil && fmt.Sprintf("%v", driver) != fmt.Sprintf("%v", newDriver) {
+		return fmt.Errorf("the backend file is for storage driver %v, but backend %s uses %v",
+			newDriver, backend.Name, driver)
+	}
+
+	if newName, err := getFieldValue(newConfig, "backendName"); err == nil && newName != nil &&
+		fmt.Sprintf("%v", newName) != "" && fmt.Sprintf("%v", newName) != backend.Name {
+		return fmt.Errorf("the backend file is for backend %v, not %s", newName, backend.Name)
+	}
+
+	return nil
+}
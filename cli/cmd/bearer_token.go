@@ -0,0 +1,55 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+var (
+	Token     string
+	TokenFile string
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&Token, "token", "",
+		"Bearer token sent to the Trident REST interface, e.g. when it is behind an authenticating proxy")
+	RootCmd.PersistentFlags().StringVar(&TokenFile, "token-file", "",
+		"File containing a bearer token sent to the Trident REST interface")
+}
+
+// applyBearerToken sets the bearer token from --token or, failing that, --token-file, overriding any
+// token from a profile or Secret.  In tunnel mode the token is ignored, since the REST requests are
+// made inside the Trident pod, which doesn't need one.
+func applyBearerToken() error {
+
+	if Token == "" && TokenFile == "" {
+		return nil
+	}
+	if OperatingMode == ModeTunnel {
+		log.Debug("Ignoring the bearer token, since commands are run in the Trident pod.")
+		return nil
+	}
+
+	if Token != "" {
+		api.BearerToken = Token
+		return nil
+	}
+
+	tokenBytes, err := ioutil.ReadFile(TokenFile)
+	if err != nil {
+		return fmt.Errorf("could not read token file; %v", err)
+	}
+	token := strings.TrimSpace(string(tokenBytes))
+	if token == "" {
+		return fmt.Errorf("token file %s is empty", TokenFile)
+	}
+	api.BearerToken = token
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+var (
+	benchConcurrency int
+	benchDuration    time.Duration
+)
+
+func init() {
+	RootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchGetCmd)
+	benchGetCmd.AddCommand(benchGetVolumeCmd)
+	benchCmd.PersistentFlags().IntVar(&benchConcurrency, "concurrency", 1, "Number of concurrent requests")
+	benchCmd.PersistentFlags().DurationVar(&benchDuration, "duration", 10*time.Second, "Length of the benchmark")
+}
+
+var benchCmd = &cobra.Command{
+	Use:    "bench",
+	Short:  "Benchmark the Trident REST interface (read-only)",
+	Hidden: true,
+}
+
+var benchGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Benchmark reading resources from Trident",
+}
+
+var benchGetVolumeCmd = &cobra.Command{
+	Use:         "volume",
+	Short:       "Benchmark listing volumes from Trident",
+	Aliases:     []string{"v", "volumes"},
+	Annotations: map[string]string{annotationREST: "GET /volume"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{
+				"bench", "get", "volume",
+				"--concurrency", strconv.Itoa(benchConcurrency),
+				"--duration", benchDuration.String(),
+			}
+			var result BenchmarkResult
+			if err := TunnelCommandJSON(command, &result); err != nil {
+				return err
+			}
+			WriteBenchmarkResult(result)
+			return nil
+		} else {
+			baseURL, err := GetBaseURL()
+			if err != nil {
+				return err
+			}
+			return benchmarkGet(baseURL + "/volume")
+		}
+	},
+}
+
+type BenchmarkResult struct {
+	URL         string        `json:"url"`
+	Concurrency int           `json:"concurrency"`
+	Duration    time.Duration `json:"duration"`
+	Requests    int           `json:"requests"`
+	Failures    int           `json:"failures"`
+	Throughput  float64       `json:"throughput"`
+	LatencyP50  time.Duration `json:"latencyP50"`
+	LatencyP90  time.Duration `json:"latencyP90"`
+	LatencyP99  time.Duration `json:"latencyP99"`
+	LatencyMax  time.Duration `json:"latencyMax"`
+}
+
+// benchmarkGet repeatedly reads the specified URL from a number of concurrent workers
+// for the benchmark duration, and then reports the throughput and latency.
+func benchmarkGet(url string) error {
+
+	if benchConcurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+	if benchDuration <= 0 {
+		return errors.New("duration must be greater than zero")
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mutex     sync.Mutex
+		latencies []time.Duration
+		failures  int
+	)
+
+	start := time.Now()
+	deadline := start.Add(benchDuration)
+
+	for i := 0; i < benchConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				requestStart := time.Now()
+				response, _, err := api.InvokeRESTAPI("GET", url, nil, false)
+				latency := time.Since(requestStart)
+
+				mutex.Lock()
+				if err != nil || response.StatusCode != http.StatusOK {
+					failures++
+				} else {
+					latencies = append(latencies, latency)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	result := BenchmarkResult{
+		URL:         url,
+		Concurrency: benchConcurrency,
+		Duration:    elapsed,
+		Requests:    len(latencies) + failures,
+		Failures:    failures,
+		Throughput:  float64(len(latencies)) / elapsed.Seconds(),
+		LatencyP50:  latencyPercentile(latencies, 50),
+		LatencyP90:  latencyPercentile(latencies, 90),
+		LatencyP99:  latencyPercentile(latencies, 99),
+		LatencyMax:  latencyPercentile(latencies, 100),
+	}
+
+	WriteBenchmarkResult(result)
+
+	return nil
+}
+
+// latencyPercentile returns the specified percentile of a sorted list of latencies.
+func latencyPercentile(latencies []time.Duration, percentile float64) time.Duration {
+
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	index := int(math.Ceil(percentile/100*float64(len(latencies)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return latencies[index]
+}
+
+func WriteBenchmarkResult(result BenchmarkResult) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(result)
+	case FormatJSONPath:
+		WriteJSONPath(result)
+	case FormatYAML:
+		WriteYAML(result)
+	default:
+		writeBenchmarkTable(result)
+	}
+}
+
+func writeBenchmarkTable(result BenchmarkResult) {
+
+	table := newTableWriter()
+	table.SetHeader([]string{"Requests", "Failures", "Requests/sec", "P50", "P90", "P99", "Max"})
+
+	table.Append([]string{
+		strconv.Itoa(result.Requests),
+		strconv.Itoa(result.Failures),
+		fmt.Sprintf("%.1f", result.Throughput),
+		result.LatencyP50.String(),
+		result.LatencyP90.String(),
+		result.LatencyP99.String(),
+		result.LatencyMax.String(),
+	})
+
+	table.Render()
+}
@@ -0,0 +1,242 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8s "k8s.io/api/core/v1"
+)
+
+const (
+	cacheDirectoryName = "tridentctl"
+	cacheKindNamespace = "namespace"
+	cacheKindCLI       = "cli"
+	cacheKindPod       = "pod"
+)
+
+var (
+	CacheTTL time.Duration
+	NoCache  bool
+)
+
+func init() {
+	RootCmd.PersistentFlags().DurationVar(&CacheTTL, "cache-ttl", 0,
+		"Cache discovery results for this long, per Kubernetes context (default no caching)")
+	RootCmd.PersistentFlags().BoolVar(&NoCache, "no-cache", false, "Ignore any cached discovery results")
+}
+
+type cacheEntry struct {
+	Value     string    `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// getCacheDirectory returns the directory where tridentctl caches discovery results.
+func getCacheDirectory() string {
+
+	if cacheHome := os.Getenv("XDG_CACHE_HOME"); cacheHome != "" {
+		return filepath.Join(cacheHome, cacheDirectoryName)
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", cacheDirectoryName)
+}
+
+// getCacheFilePath returns the path of the file caching the specified kind of value for a key.
+func getCacheFilePath(kind, key string) string {
+	return filepath.Join(getCacheDirectory(), kind+"-"+url.QueryEscape(key)+".json")
+}
+
+// readCache returns a cached value if it exists and is no older than the cache TTL.
+func readCache(kind, key string) (string, bool) {
+
+	cacheBytes, err := ioutil.ReadFile(getCacheFilePath(kind, key))
+	if err != nil {
+		return "", false
+	}
+
+	var entry cacheEntry
+	if err = json.Unmarshal(cacheBytes, &entry); err != nil {
+		return "", false
+	}
+
+	if time.Since(entry.Timestamp) > CacheTTL || entry.Value == "" {
+		return "", false
+	}
+
+	return entry.Value, true
+}
+
+// writeCache caches a value.  Failures are not fatal, since the value may always be rediscovered.
+func writeCache(kind, key, value string) {
+
+	entry := cacheEntry{Value: value, Timestamp: time.Now()}
+
+	err := os.MkdirAll(getCacheDirectory(), 0700)
+	if err == nil {
+		var cacheBytes []byte
+		if cacheBytes, err = json.Marshal(entry); err == nil {
+			err = ioutil.WriteFile(getCacheFilePath(kind, key), cacheBytes, 0600)
+		}
+	}
+
+	if err != nil {
+		log.Debugf("Could not cache %s; %v", kind, err)
+	}
+}
+
+// kubernetesCacheKey returns the key under which values discovered from a Kubernetes context are
+// cached.  Besides the context, it includes the kubeconfig holding the context and any cluster or
+// user overriding the context's own, since any of them may point the Kubernetes CLI elsewhere.
+func kubernetesCacheKey(context string) string {
+
+	kubeconfig := Kubeconfig
+	if kubeconfig == "" {
+		kubeconfig = os.Getenv("KUBECONFIG")
+	}
+	return strings.Join([]string{kubeconfig, context, KubeCluster, KubeUser}, "|")
+}
+
+// getKubernetesCacheKey returns the cache key for the current Kubernetes context, so that switching
+// contexts doesn't reuse values discovered from another cluster.
+func getKubernetesCacheKey() (string, error) {
+
+	context, err := getCurrentContext()
+	if err != nil {
+		return "", err
+	}
+	return kubernetesCacheKey(context), nil
+}
+
+// getCachedNamespace returns the current namespace, using a value cached for the current
+// Kubernetes context if caching is enabled.  If the context can't be determined, the namespace is
+// looked up without the cache.
+func getCachedNamespace() (string, error) {
+
+	if CacheTTL <= 0 {
+		return getCurrentNamespace()
+	}
+
+	key, err := getKubernetesCacheKey()
+	if err != nil {
+		log.Debugf("Not caching the namespace; %v", err)
+		return getCurrentNamespace()
+	}
+
+	if !NoCache {
+		if namespace, ok := readCache(cacheKindNamespace, key); ok {
+			log.Debugf("Using cached namespace %s for %s.", namespace, key)
+			return namespace, nil
+		}
+	}
+
+	namespace, err := getCurrentNamespace()
+	if err != nil {
+		return "", err
+	}
+
+	writeCache(cacheKindNamespace, key, namespace)
+
+	return namespace, nil
+}
+
+// getCachedKubernetesCLI discovers the Kubernetes CLI, using a CLI cached for the kubeconfig and
+// context if caching is enabled.  The current context can't be found without a CLI, so unlike the
+// other values, the CLI is cached by any context specified with --context rather than the current one.
+func getCachedKubernetesCLI() error {
+
+	if CacheTTL <= 0 || KubernetesCLIName != "" {
+		return discoverKubernetesCLI()
+	}
+
+	key := kubernetesCacheKey(KubernetesContext)
+
+	if !NoCache {
+		if cli, ok := readCache(cacheKindCLI, key); ok && (cli == CLIKubernetes || cli == CLIOpenshift) {
+			if _, err := exec.LookPath(cli); err == nil {
+				log.Debugf("Using cached Kubernetes CLI %s.", cli)
+				KubernetesCLI = cli
+				return nil
+			}
+		}
+	}
+
+	if err := discoverKubernetesCLI(); err != nil {
+		return err
+	}
+
+	writeCache(cacheKindCLI, key, KubernetesCLI)
+
+	return nil
+}
+
+// getCachedTridentPod returns the Trident pod in the specified namespace, using the pod cached for
+// the current Kubernetes context if caching is enabled.  A cached pod that is no longer running is
+// ignored, and the pod is discovered again, as it is if the context can't be determined.
+func getCachedTridentPod(namespace string) (*k8s.Pod, error) {
+
+	if CacheTTL <= 0 {
+		return discoverTridentPod(namespace)
+	}
+
+	clusterKey, err := getKubernetesCacheKey()
+	if err != nil {
+		log.Debugf("Not caching the Trident pod; %v", err)
+		return discoverTridentPod(namespace)
+	}
+
+	// The pod found also depends on the options that select it
+	key := strings.Join([]string{clusterKey, namespace, PodSelector, Workload, strconv.FormatBool(CSI)}, "/")
+
+	if !NoCache {
+		if value, ok := readCache(cacheKindPod, key); ok {
+			tridentPod, err := getRunningCachedPod(value)
+			if err == nil {
+				log.Debugf("Using cached Trident pod %s for %s.", value, clusterKey)
+
+				// The pod was just found running, so it needn't be checked again before tunneling
+				tridentPodCached, tridentPodRefreshed = true, true
+				return tridentPod, nil
+			} else {
+				log.Debugf("Cached Trident pod %s is stale, repeating discovery; %v", value, err)
+			}
+		}
+	}
+
+	tridentPod, err := discoverTridentPod(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCache(cacheKindPod, key, tridentPod.Namespace+"/"+tridentPod.Name)
+
+	return tridentPod, nil
+}
+
+// getRunningCachedPod returns the pod named by a cached value of the form <namespace>/<name>, if the
+// pod is still running.
+func getRunningCachedPod(value string) (*k8s.Pod, error) {
+
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cached pod %s", value)
+	}
+
+	pod, err := getPod(parts[0], parts[1])
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != k8s.PodRunning || pod.DeletionTimestamp != nil {
+		return nil, fmt.Errorf("pod %s is no longer running", value)
+	}
+
+	return pod, nil
+}
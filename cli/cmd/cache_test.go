@@ -0,0 +1,125 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// useTestCacheDirectory points the cache at a temporary directory for the duration of a test.
+func useTestCacheDirectory(t *testing.T) func() {
+
+	cacheHome, err := ioutil.TempDir("", "tridentctl-cache")
+	if err != nil {
+		t.Fatalf("Could not create cache directory; %v", err)
+	}
+
+	previousCacheHome, previousTTL := os.Getenv("XDG_CACHE_HOME"), CacheTTL
+	os.Setenv("XDG_CACHE_HOME", cacheHome)
+
+	return func() {
+		os.Setenv("XDG_CACHE_HOME", previousCacheHome)
+		CacheTTL = previousTTL
+		os.RemoveAll(cacheHome)
+	}
+}
+
+// writeTestCacheEntry caches a value as if it had been written at the specified time.
+func writeTestCacheEntry(t *testing.T, kind, key, value string, timestamp time.Time) {
+
+	if err := os.MkdirAll(getCacheDirectory(), 0700); err != nil {
+		t.Fatalf("Could not create cache directory; %v", err)
+	}
+
+	cacheBytes, err := json.Marshal(cacheEntry{Value: value, Timestamp: timestamp})
+	if err != nil {
+		t.Fatalf("Could not marshal cache entry; %v", err)
+	}
+	if err = ioutil.WriteFile(getCacheFilePath(kind, key), cacheBytes, 0600); err != nil {
+		t.Fatalf("Could not write cache entry; %v", err)
+	}
+}
+
+func TestReadCacheWithinTTL(t *testing.T) {
+
+	defer useTestCacheDirectory(t)()
+	CacheTTL = time.Minute
+
+	writeCache(cacheKindPod, "context/trident", "trident/trident-abc")
+
+	value, ok := readCache(cacheKindPod, "context/trident")
+	assert.True(t, ok, "expected a cached value")
+	assert.Equal(t, "trident/trident-abc", value)
+}
+
+func TestReadCacheExpired(t *testing.T) {
+
+	defer useTestCacheDirectory(t)()
+	CacheTTL = time.Minute
+
+	writeTestCacheEntry(t, cacheKindPod, "context/trident", "trident/trident-abc",
+		time.Now().Add(-2*time.Minute))
+
+	_, ok := readCache(cacheKindPod, "context/trident")
+	assert.False(t, ok, "expected the cached value to have expired")
+
+	// A longer TTL makes the same entry current again
+	CacheTTL = time.Hour
+	value, ok := readCache(cacheKindPod, "context/trident")
+	assert.True(t, ok, "expected a cached value")
+	assert.Equal(t, "trident/trident-abc", value)
+}
+
+func TestReadCacheInvalid(t *testing.T) {
+
+	defer useTestCacheDirectory(t)()
+	CacheTTL = time.Minute
+
+	writeCache(cacheKindCLI, "kubeconfig|", "kubectl")
+
+	// Overwrite the entry with something that isn't one
+	if err := ioutil.WriteFile(getCacheFilePath(cacheKindCLI, "kubeconfig|"), []byte("not json"), 0600); err != nil {
+		t.Fatalf("Could not write cache entry; %v", err)
+	}
+
+	_, ok := readCache(cacheKindCLI, "kubeconfig|")
+	assert.False(t, ok, "expected an invalid cache entry to be ignored")
+
+	_, ok = readCache(cacheKindCLI, "missing")
+	assert.False(t, ok, "expected a missing cache entry to be ignored")
+}
+
+func TestGetRunningCachedPodInvalid(t *testing.T) {
+
+	for _, value := range []string{"trident-abc", "/trident-abc", "trident/"} {
+		_, err := getRunningCachedPod(value)
+		assert.Error(t, err, "expected an error for %s", value)
+	}
+}
+
+func TestKubernetesCacheKey(t *testing.T) {
+
+	defer func(kubeconfig, cluster, user string) {
+		Kubeconfig, KubeCluster, KubeUser = kubeconfig, cluster, user
+	}(Kubeconfig, KubeCluster, KubeUser)
+
+	Kubeconfig, KubeCluster, KubeUser = "/tmp/kubeconfig", "", ""
+	key := kubernetesCacheKey("prod")
+
+	assert.NotEqual(t, key, kubernetesCacheKey("test"), "expected the context to be part of the key")
+
+	KubeCluster = "prod-east"
+	assert.NotEqual(t, key, kubernetesCacheKey("prod"), "expected the cluster to be part of the key")
+
+	KubeCluster, KubeUser = "", "admin"
+	assert.NotEqual(t, key, kubernetesCacheKey("prod"), "expected the user to be part of the key")
+
+	KubeUser, Kubeconfig = "", "/tmp/other-kubeconfig"
+	assert.NotEqual(t, key, kubernetesCacheKey("prod"), "expected the kubeconfig to be part of the key")
+}
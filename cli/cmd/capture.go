@@ -0,0 +1,94 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	capturedStdout *os.File
+	capturePipe    *os.File
+	capturedOutput bytes.Buffer
+	captureDone    chan struct{}
+)
+
+func init() {
+	cobra.OnInitialize(initOutputCapture)
+}
+
+// initOutputCapture tees stdout into a buffer if the result of the command is needed once it
+// finishes, such as for the audit log or the clipboard.  A result written to a file is only captured,
+// not written to stdout.
+func initOutputCapture() {
+
+	if (AuditLog == "" && !Clipboard && OutputFile == "") || capturePipe != nil {
+		return
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not capture the result of the command; %v\n", err)
+		return
+	}
+
+	capturedStdout, capturePipe = os.Stdout, writer
+	os.Stdout = writer
+
+	var captureWriter io.Writer = io.MultiWriter(capturedStdout, &capturedOutput)
+	if OutputFile != "" {
+		captureWriter = &capturedOutput
+	}
+
+	captureDone = make(chan struct{})
+	go func() {
+		io.Copy(captureWriter, reader)
+		reader.Close()
+		close(captureDone)
+	}()
+}
+
+// resultStdout returns the stdout through which the result reaches the user, which is the original
+// stdout while the result is captured.
+func resultStdout() *os.File {
+	if capturedStdout != nil && capturePipe != nil {
+		return capturedStdout
+	}
+	return os.Stdout
+}
+
+// FinishOutput restores stdout once the command has finished, and then delivers its result to the
+// output file, audit log, and clipboard as requested.  If the output file can't be written, the exit
+// code is changed to report the failure.
+func FinishOutput(exitCode int) {
+
+	if capturePipe == nil {
+		return
+	}
+
+	os.Stdout = capturedStdout
+	capturePipe.Close()
+	<-captureDone
+	capturePipe = nil
+
+	if OutputFile != "" {
+		if exitCode != ExitCodeSuccess {
+			// Only a successful result is saved, so show whatever a failed command printed
+			capturedStdout.Write(capturedOutput.Bytes())
+		} else if err := writeOutputFile(OutputFile, capturedOutput.Bytes()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exitCode = ExitCodeFailure
+			ExitCode = exitCode
+		}
+	}
+
+	writeAuditEntry(exitCode, capturedOutput.Bytes())
+	if Clipboard {
+		copyToClipboard(capturedOutput.Bytes())
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+)
+
+// maxCLIVersionSkew is the number of minor versions the Kubernetes CLI is supported within of the server
+const maxCLIVersionSkew = 1
+
+// kubernetesVersions is the output of 'kubectl version -o json'.  The server version is missing if
+// the server couldn't be reached, and the OpenShift version is reported by 'oc' only for OpenShift.
+type kubernetesVersions struct {
+	ClientVersion    *k8sversion.Info `json:"clientVersion"`
+	ServerVersion    *k8sversion.Info `json:"serverVersion"`
+	OpenShiftVersion string           `json:"openshiftVersion"`
+}
+
+// checkKubernetesCLIVersion checks from its probe that a Kubernetes CLI can reach the cluster, so that a
+// bad kubeconfig is reported as such rather than as a missing Trident pod, and warns if the CLI is too
+// old or too new for the server.  A CLI that can't report its version as JSON is accepted as it is.
+func checkKubernetesCLIVersion(probe cliProbe) error {
+
+	recordCommand(probe.cmd, probe.stderr, probe.err)
+
+	versions := probe.versions
+	if versions == nil {
+		log.Debugf("Could not check the version of the %s CLI, which did not report it as JSON.", probe.cli)
+		return nil
+	}
+
+	if versions.ServerVersion == nil {
+		if probe.err != nil {
+			return getDiscoveryError(probe.err, probe.stderr)
+		}
+		return nil
+	}
+
+	log.Debugf("The %s CLI version is %s, and the Kubernetes server version is %s.", probe.cli,
+		versions.ClientVersion.GitVersion, versions.ServerVersion.GitVersion)
+
+	skew, ok := minorVersionSkew(versions.ClientVersion, versions.ServerVersion)
+	if ok && skew > maxCLIVersionSkew {
+		writeWarning("the %s CLI version %s is more than %d minor version(s) from the Kubernetes server version "+
+			"%s, so some commands may fail", probe.cli, versions.ClientVersion.GitVersion, maxCLIVersionSkew,
+			versions.ServerVersion.GitVersion)
+	}
+
+	return nil
+}
+
+// minorVersionSkew returns how many minor versions apart a client and server are.  It returns false if
+// either version can't be parsed or their major versions differ, as the OpenShift CLI may report.
+func minorVersionSkew(client, server *k8sversion.Info) (int, bool) {
+
+	clientMajor, clientMinor, ok := parseMajorMinor(client)
+	if !ok {
+		return 0, false
+	}
+	serverMajor, serverMinor, ok := parseMajorMinor(server)
+	if !ok || clientMajor != serverMajor {
+		return 0, false
+	}
+
+	if clientMinor > serverMinor {
+		return clientMinor - serverMinor, true
+	}
+	return serverMinor - clientMinor, true
+}
+
+// parseMajorMinor returns the major and minor versions, ignoring any suffix such as the '+' of
+// managed clusters, e.g. a minor version of '14+'.
+func parseMajorMinor(info *k8sversion.Info) (int, int, bool) {
+
+	major, err := strconv.Atoi(strings.TrimRight(info.Major, "+"))
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(strings.TrimRight(info.Minor, "+"))
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
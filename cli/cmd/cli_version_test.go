@@ -0,0 +1,32 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+)
+
+func TestMinorVersionSkew(t *testing.T) {
+
+	tests := []struct {
+		client, server *k8sversion.Info
+		skew           int
+		ok             bool
+	}{
+		{&k8sversion.Info{Major: "1", Minor: "14"}, &k8sversion.Info{Major: "1", Minor: "14"}, 0, true},
+		{&k8sversion.Info{Major: "1", Minor: "12"}, &k8sversion.Info{Major: "1", Minor: "14+"}, 2, true},
+		{&k8sversion.Info{Major: "1", Minor: "15"}, &k8sversion.Info{Major: "1", Minor: "13"}, 2, true},
+		{&k8sversion.Info{Major: "4", Minor: "2"}, &k8sversion.Info{Major: "1", Minor: "14"}, 0, false},
+		{&k8sversion.Info{Major: "", Minor: ""}, &k8sversion.Info{Major: "1", Minor: "14"}, 0, false},
+	}
+
+	for _, test := range tests {
+		skew, ok := minorVersionSkew(test.client, test.server)
+		assert.Equal(t, test.ok, ok, "unexpected result for %s.%s and %s.%s",
+			test.client.Major, test.client.Minor, test.server.Major, test.server.Minor)
+		assert.Equal(t, test.skew, skew)
+	}
+}
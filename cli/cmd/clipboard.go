@@ -0,0 +1,65 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var Clipboard bool
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&Clipboard, "clipboard", false,
+		"Also copy the result to the system clipboard")
+}
+
+// getClipboardCommand returns the first available command that copies its stdin to the clipboard.
+func getClipboardCommand() ([]string, error) {
+
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"})
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return nil, errors.New("no clipboard utility was found")
+}
+
+// copyToClipboard copies the result of a command to the clipboard.  The result has already been
+// printed, so failures are reported as warnings only.
+func copyToClipboard(result []byte) {
+
+	clipboardCommand, err := getClipboardCommand()
+	if err == nil {
+		cmd := exec.Command(clipboardCommand[0], clipboardCommand[1:]...)
+		cmd.Stdin = bytes.NewReader(result)
+		err = cmd.Run()
+	}
+
+	if err != nil {
+		writeWarning("could not copy the result to the clipboard; %v", err)
+	} else {
+		log.Debugf("Copied the result to the clipboard with %s.", clipboardCommand[0])
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsSource      = "tridentctl"
+	cloudEventsTypePrefix  = "io.netapp.trident."
+)
+
+// CloudEvent is a CloudEvents envelope wrapping one result object.
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// WriteCloudEvents writes each item as a CloudEvents envelope, one JSON event per line, so that the
+// output may be published directly to an event bus.
+func WriteCloudEvents(kind string, items []interface{}) {
+
+	for _, item := range items {
+
+		event := CloudEvent{
+			SpecVersion:     cloudEventsSpecVersion,
+			ID:              newCloudEventID(),
+			Source:          cloudEventsSource,
+			Type:            cloudEventsTypePrefix + kind,
+			Time:            time.Now().UTC(),
+			DataContentType: "application/json",
+			Data:            item,
+		}
+		if name, err := getFieldValue(item, "name"); err == nil && name != nil {
+			event.Subject = fmt.Sprintf("%v", name)
+		}
+		if Redact {
+			event.Data = redactSensitiveFields(event.Data)
+		}
+
+		eventBytes, _ := json.Marshal(event)
+		fmt.Println(string(eventBytes))
+	}
+}
+
+// newCloudEventID returns a random ID, unique to each event.
+func newCloudEventID() string {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(idBytes)
+}
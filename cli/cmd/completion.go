@@ -0,0 +1,56 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	shellBash = "bash"
+	shellZsh  = "zsh"
+
+	bashCompletionOutputFunction = "__tridentctl_output_formats"
+)
+
+func init() {
+	RootCmd.AddCommand(completionCmd)
+
+	// Bash completion suggests the output formats by calling a function defined in the completion script
+	RootCmd.BashCompletionFunction = fmt.Sprintf(`
+%s()
+{
+    COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+}
+`, bashCompletionOutputFunction, strings.Join(outputFormats, " "))
+	RootCmd.PersistentFlags().SetAnnotation("output", cobra.BashCompCustom, []string{bashCompletionOutputFunction})
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <shell>",
+	Short: "Print the shell completion script for tridentctl",
+	Long: `Print the shell completion script for tridentctl, for bash or zsh.
+
+To enable completion in the current bash session, run:
+
+  source <(tridentctl completion bash)
+
+To enable it in every zsh session, write the script to a file named _tridentctl
+in a directory listed in $fpath.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationSkipDiscovery: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case shellBash:
+			return RootCmd.GenBashCompletion(os.Stdout)
+		case shellZsh:
+			return RootCmd.GenZshCompletion(os.Stdout)
+		default:
+			return fmt.Errorf("unsupported shell %s; expected bash or zsh", args[0])
+		}
+	},
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+const (
+	userConfigDirectory = ".tridentctl"
+	userConfigFilename  = "config.yaml"
+	localConfigFilename = ".tridentctl.yaml"
+)
+
+var (
+	clientConfig = &ClientConfig{}
+
+	// ConfigFile, if set, is read instead of the user-global and per-directory config files
+	ConfigFile string
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&ConfigFile, "config", "",
+		"Path of the tridentctl config file to read instead of the default config files")
+}
+
+// ClientConfig contains defaults for tridentctl's global options.  Defaults may be set in the
+// user-global config file ($HOME/.tridentctl/config.yaml) and in a per-directory config file
+// (.tridentctl.yaml), which is found by searching the current directory and its parents.
+// The effective value of each option is determined in this order, highest precedence first:
+//
+//  1. command-line flag
+//  2. environment variable (where one exists)
+//  3. profile in the Trident config file (--trident-config)
+//  4. per-directory config file
+//  5. user-global config file
+//
+// A config file specified with --config replaces both config files.  The Trident namespace may also
+// be configured per Kubernetes context, which takes precedence over the namespace configured for all
+// contexts.
+type ClientConfig struct {
+	Server            string            `json:"server,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	ContextNamespaces map[string]string `json:"contextNamespaces,omitempty"`
+	Output            string            `json:"output,omitempty"`
+	PodSelector       string            `json:"podSelector,omitempty"`
+}
+
+// merge overlays any values set in the other config onto this one.
+func (c *ClientConfig) merge(other *ClientConfig) {
+	if other.Server != "" {
+		c.Server = other.Server
+	}
+	if other.Namespace != "" {
+		c.Namespace = other.Namespace
+	}
+	for context, namespace := range other.ContextNamespaces {
+		if c.ContextNamespaces == nil {
+			c.ContextNamespaces = make(map[string]string)
+		}
+		c.ContextNamespaces[context] = namespace
+	}
+	if other.Output != "" {
+		c.Output = other.Output
+	}
+	if other.PodSelector != "" {
+		c.PodSelector = other.PodSelector
+	}
+}
+
+// getConfigFilePaths returns the paths of any config files that exist, lowest precedence first.
+func getConfigFilePaths() []string {
+
+	// A config file specified explicitly is read even if it doesn't exist, so that the error is reported
+	if ConfigFile != "" {
+		return []string{ConfigFile}
+	}
+
+	var paths []string
+
+	if home := os.Getenv("HOME"); home != "" {
+		userConfigPath := filepath.Join(home, userConfigDirectory, userConfigFilename)
+		if _, err := os.Stat(userConfigPath); err == nil {
+			paths = append(paths, userConfigPath)
+		}
+	}
+
+	// Use the config file closest to the current directory
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			localConfigPath := filepath.Join(dir, localConfigFilename)
+			if _, err := os.Stat(localConfigPath); err == nil {
+				paths = append(paths, localConfigPath)
+				break
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+
+	return paths
+}
+
+// readClientConfig reads and merges all available config files.
+func readClientConfig() (*ClientConfig, error) {
+
+	mergedConfig := &ClientConfig{}
+
+	for _, path := range getConfigFilePaths() {
+
+		configBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read config file %s; %v", path, err)
+		}
+
+		fileConfig := &ClientConfig{}
+		if err = yaml.Unmarshal(configBytes, fileConfig); err != nil {
+			return nil, fmt.Errorf("could not parse config file %s; %v", path, err)
+		}
+
+		log.Debugf("Read config file %s: %+v", path, *fileConfig)
+
+		mergedConfig.merge(fileConfig)
+	}
+
+	return mergedConfig, nil
+}
+
+// loadClientConfig applies defaults from the config files to any global options
+// not already set on the command line or in the environment.
+func loadClientConfig(cmd *cobra.Command) error {
+
+	var err error
+	if clientConfig, err = readClientConfig(); err != nil {
+		return err
+	}
+
+	// A Trident connection profile takes precedence over the config files
+	if TridentConfigFile != "" {
+		profile, err := readTridentProfile(TridentConfigFile, TridentProfileName)
+		if err != nil {
+			return err
+		}
+		clientConfig.merge(&ClientConfig{Server: profile.Server, Namespace: profile.Namespace})
+		if err = applyTridentProfile(profile); err != nil {
+			return err
+		}
+	}
+
+	flags := cmd.Flags()
+
+	if !flags.Changed("server") && os.Getenv("TRIDENT_SERVER") == "" && clientConfig.Server != "" {
+		Server = clientConfig.Server
+	}
+	if !flags.Changed("output") && clientConfig.Output != "" {
+		OutputFormat = clientConfig.Output
+	}
+	if !flags.Changed("pod-selector") && clientConfig.PodSelector != "" {
+		PodSelector = clientConfig.PodSelector
+	}
+
+	if InsecureSkipTLSVerify {
+		api.SetInsecureSkipVerify()
+	}
+
+	return nil
+}
+
+// getConfiguredNamespace returns the Trident namespace set in the config files, if any.  A namespace
+// configured for the current Kubernetes context is preferred.
+func getConfiguredNamespace() (string, error) {
+
+	if len(clientConfig.ContextNamespaces) > 0 {
+
+		context, err := getCurrentContext()
+		if err != nil {
+			return "", err
+		}
+
+		if namespace, ok := clientConfig.ContextNamespaces[context]; ok {
+			return namespace, nil
+		}
+	}
+
+	return clientConfig.Namespace, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+func init() {
+	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configResolvedCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:         "config",
+	Short:       "Show the configuration of tridentctl",
+	Annotations: map[string]string{annotationSkipDiscovery: "true"},
+}
+
+var configResolvedCmd = &cobra.Command{
+	Use:   "resolved",
+	Short: "Print the effective configuration after merging flags, environment, and config files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		// Report a failed discovery rather than failing, since the configuration explains the failure
+		resolvedConfig := ResolvedConfig{}
+		if err := discoverOperatingMode(cmd); err != nil {
+			resolvedConfig.DiscoveryError = err.Error()
+		}
+		resolvedConfig.resolve()
+
+		WriteResolvedConfig(resolvedConfig)
+		return nil
+	},
+}
+
+// ResolvedConfig is the effective configuration of tridentctl, for reproducing a user's setup.
+type ResolvedConfig struct {
+	ConfigFiles      []string `json:"configFiles"`
+	TridentConfig    string   `json:"tridentConfig,omitempty"`
+	TridentProfile   string   `json:"tridentProfile,omitempty"`
+	Token            string   `json:"token,omitempty"`
+	OperatingMode    string   `json:"operatingMode"`
+	Server           string   `json:"server"`
+	KubernetesCLI    string   `json:"kubernetesCLI,omitempty"`
+	Kubeconfig       string   `json:"kubeconfig,omitempty"`
+	Context          string   `json:"context,omitempty"`
+	Namespace        string   `json:"namespace,omitempty"`
+	TridentPod       string   `json:"tridentPod,omitempty"`
+	Container        string   `json:"container,omitempty"`
+	PodSelector      string   `json:"podSelector"`
+	Workload         string   `json:"workload"`
+	HelmRelease      string   `json:"helmRelease,omitempty"`
+	TunnelEntrypoint string   `json:"tunnelEntrypoint,omitempty"`
+	BasePath         string   `json:"basePath,omitempty"`
+	APIVersion       string   `json:"apiVersion"`
+	Output           string   `json:"output"`
+	HTTPTimeout      string   `json:"httpTimeout"`
+	PodReadyTimeout  string   `json:"podReadyTimeout"`
+	CacheTTL         string   `json:"cacheTTL"`
+	LogDestination   string   `json:"logDestination"`
+	DiscoveryError   string   `json:"discoveryError,omitempty"`
+}
+
+// resolve fills in the configuration from the global options, which discovery has resolved.
+func (c *ResolvedConfig) resolve() {
+
+	c.ConfigFiles = getConfigFilePaths()
+	c.TridentConfig = TridentConfigFile
+	c.TridentProfile = TridentProfileName
+	if api.BearerToken != "" {
+		c.Token = redactedValue
+	}
+	c.OperatingMode = OperatingMode
+	c.Server = Server
+	c.Kubeconfig = Kubeconfig
+	c.PodSelector = PodSelector
+	c.Workload = Workload
+	c.HelmRelease = HelmRelease
+	c.BasePath = BasePath
+	c.APIVersion = APIVersion
+	c.Output = OutputFormat
+	c.HTTPTimeout = api.HTTPTimeout.String()
+	c.PodReadyTimeout = PodReadyTimeout.String()
+	c.CacheTTL = CacheTTL.String()
+	c.LogDestination = LogDestination
+
+	if OperatingMode == ModeTunnel {
+		c.KubernetesCLI = KubernetesCLI
+		c.Context, _ = getCurrentContext()
+		c.Namespace = TridentPodNamespace
+		c.TridentPod = TridentPodName
+		c.Container = TridentContainer
+		c.TunnelEntrypoint = TunnelEntrypoint
+	}
+}
+
+// WriteResolvedConfig writes the resolved configuration, as YAML unless JSON was requested.
+// Sensitive values are always redacted.
+func WriteResolvedConfig(resolvedConfig ResolvedConfig) {
+
+	redactedConfig := redactSensitiveFields(resolvedConfig)
+
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(redactedConfig)
+	case FormatJSONPath:
+		WriteJSONPath(redactedConfig)
+	default:
+		WriteYAML(redactedConfig)
+	}
+}
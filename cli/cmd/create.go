@@ -14,8 +14,4 @@ func init() {
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Add a resource to Trident",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		err := discoverOperatingMode(cmd)
-		return err
-	},
 }
@@ -8,8 +8,11 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
@@ -21,15 +24,16 @@ import (
 
 func init() {
 	createCmd.AddCommand(createBackendCmd)
-	createBackendCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path to YAML or JSON file")
+	createBackendCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path or http, https, or file URL of YAML or JSON file")
 	createBackendCmd.Flags().StringVarP(&b64Data, "base64", "", "", "Base64 encoding")
 	createBackendCmd.Flags().MarkHidden("base64")
 }
 
 var createBackendCmd = &cobra.Command{
-	Use:     "backend",
-	Short:   "Add a backend to Trident",
-	Aliases: []string{"b"},
+	Use:         "backend",
+	Short:       "Add a backend to Trident",
+	Aliases:     []string{"b"},
+	Annotations: map[string]string{annotationREST: "POST /backend"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		jsonData, err := getBackendData()
@@ -39,7 +43,11 @@ var createBackendCmd = &cobra.Command{
 
 		if OperatingMode == ModeTunnel {
 			command := []string{"create", "backend", "--base64", base64.StdEncoding.EncodeToString(jsonData)}
-			TunnelCommand(append(command, args...))
+			var backends api.MultipleBackendResponse
+			if err := TunnelCommandJSON(append(command, args...), &backends); err != nil {
+				return err
+			}
+			WriteBackends(backends.Items)
 			return nil
 		} else {
 			return backendCreate(jsonData)
@@ -61,6 +69,8 @@ func getBackendData() ([]byte, error) {
 		rawData, err = base64.StdEncoding.DecodeString(b64Data)
 	} else if filename == "-" {
 		rawData, err = ioutil.ReadAll(os.Stdin)
+	} else if strings.Contains(filename, "://") {
+		rawData, err = readBackendURL(filename)
 	} else {
 		rawData, err = ioutil.ReadFile(filename)
 	}
@@ -77,6 +87,40 @@ func getBackendData() ([]byte, error) {
 	return jsonData, nil
 }
 
+// readBackendURL reads a backend file from an http, https, or file URL.  Files are fetched with the
+// same TLS and proxy settings as the REST interface.
+func readBackendURL(fileURL string) ([]byte, error) {
+
+	parsedURL, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file URL %s; %v", fileURL, err)
+	}
+
+	switch strings.ToLower(parsedURL.Scheme) {
+	case "file":
+		return ioutil.ReadFile(parsedURL.Path)
+	case "http", "https":
+		break
+	default:
+		return nil, fmt.Errorf("unsupported file URL scheme %s; expected http, https, or file", parsedURL.Scheme)
+	}
+
+	response, responseBody, err := api.Fetch(fileURL, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not read %s: %s", fileURL, response.Status)
+	}
+
+	// An HTML page is most likely a login or error page rather than the file
+	if contentType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type")); err == nil &&
+		contentType == "text/html" {
+		return nil, fmt.Errorf("could not read %s: unexpected content type %s", fileURL, contentType)
+	}
+
+	return responseBody, nil
+}
+
 func backendCreate(postData []byte) error {
 
 	baseURL, err := GetBaseURL()
@@ -0,0 +1,61 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugInfoCmd)
+}
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshoot the connection of tridentctl to Trident",
+}
+
+var debugInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Print how tridentctl would connect to Trident, without calling it",
+	Annotations: map[string]string{
+		annotationDiscoveryOptional: "true",
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		connectionInfo := ConnectionInfo{
+			OperatingMode: OperatingMode,
+			Server:        Server,
+			KubernetesCLI: KubernetesCLI,
+			Namespace:     TridentPodNamespace,
+			TridentPod:    TridentPodName,
+		}
+		if discoveryError != nil {
+			connectionInfo.DiscoveryError = discoveryError.Error()
+		}
+
+		writeOutput(connectionInfo)
+
+		// Report what was discovered before failing, since that explains the failure
+		return discoveryError
+	},
+}
+
+// ConnectionInfo describes how tridentctl reaches Trident, as discovered before running a command.
+type ConnectionInfo struct {
+	OperatingMode  string `json:"operatingMode"`
+	Server         string `json:"server"`
+	KubernetesCLI  string `json:"kubernetesCLI,omitempty"`
+	Namespace      string `json:"namespace,omitempty"`
+	TridentPod     string `json:"tridentPod,omitempty"`
+	DiscoveryError string `json:"discoveryError,omitempty"`
+}
+
+func (c ConnectionInfo) tableHeader(wide bool) []string {
+	return []string{"Mode", "Server", "Kubernetes CLI", "Namespace", "Trident Pod"}
+}
+
+func (c ConnectionInfo) tableRows(wide bool) [][]string {
+	return [][]string{{c.OperatingMode, c.Server, c.KubernetesCLI, c.Namespace, c.TridentPod}}
+}
@@ -4,15 +4,26 @@ package cmd
 
 import "github.com/spf13/cobra"
 
+var successOnNoop bool
+
 func init() {
 	RootCmd.AddCommand(deleteCmd)
+	deleteCmd.PersistentFlags().BoolVar(&successOnNoop, "success-on-noop", false,
+		"Succeed when deleting a resource that doesn't exist")
 }
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete",
-	Short: "Remove one or more resources from Trident",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		err := discoverOperatingMode(cmd)
-		return err
-	},
+	Use:         "delete",
+	Short:       "Remove one or more resources from Trident",
+	Annotations: map[string]string{annotationNamespacedArgs: "true"},
+}
+
+// getDeletePodFlags returns the delete options to pass along to a tunneled delete command.
+func getDeletePodFlags() []podFlag {
+
+	var flags []podFlag
+	if successOnNoop {
+		flags = append(flags, requiredPodFlag("--success-on-noop"))
+	}
+	return flags
 }
@@ -4,10 +4,10 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/netapp/trident/cli/api"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -19,16 +19,17 @@ func init() {
 }
 
 var deleteBackendCmd = &cobra.Command{
-	Use:     "backend <name> [<name>...]",
-	Short:   "Delete one or more storage backends from Trident",
-	Aliases: []string{"b", "backends"},
+	Use:         "backend <name> [<name>...]",
+	Short:       "Delete one or more storage backends from Trident",
+	Aliases:     []string{"b", "backends"},
+	Annotations: map[string]string{annotationREST: "DELETE /backend/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"delete", "backend"}
 			if AllBackends {
 				command = append(command, "--all")
 			}
-			TunnelCommand(append(command, args...))
+			TunnelCommand(append(command, args...), getDeletePodFlags()...)
 			return nil
 		} else {
 			return backendDelete(args)
@@ -61,17 +62,20 @@ func backendDelete(backendNames []string) error {
 		}
 	}
 
+	deleteErrors := NewMultiError(len(backendNames))
+
 	for _, backendName := range backendNames {
 		url := baseURL + "/backend/" + backendName
 
 		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
 		if err != nil {
-			return err
+			deleteErrors.Append(backendName, err)
+		} else if response.StatusCode == http.StatusNotFound && successOnNoop {
+			log.Debugf("Backend %s not found, nothing to delete.", backendName)
 		} else if response.StatusCode != http.StatusOK {
-			return fmt.Errorf("could not delete backend %s: %v", backendName,
-				GetErrorFromHTTPResponse(response, responseBody))
+			deleteErrors.AppendAction(backendName, "delete backend", GetErrorFromHTTPResponse(response, responseBody))
 		}
 	}
 
-	return nil
+	return deleteErrors.ErrorOrNil()
 }
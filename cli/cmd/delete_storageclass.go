@@ -4,10 +4,10 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/netapp/trident/cli/api"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -19,16 +19,17 @@ func init() {
 }
 
 var deleteStorageClassCmd = &cobra.Command{
-	Use:     "storageclass <name> [<name>...]",
-	Short:   "Delete one or more storage classes from Trident",
-	Aliases: []string{"sc", "storageclasses"},
+	Use:         "storageclass <name> [<name>...]",
+	Short:       "Delete one or more storage classes from Trident",
+	Aliases:     []string{"sc", "storageclasses"},
+	Annotations: map[string]string{annotationREST: "DELETE /storageclass/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"delete", "storageclass"}
 			if AllStorageClasses {
 				command = append(command, "--all")
 			}
-			TunnelCommand(append(command, args...))
+			TunnelCommand(append(command, args...), getDeletePodFlags()...)
 			return nil
 		} else {
 			return storageClassDelete(args)
@@ -61,17 +62,20 @@ func storageClassDelete(storageClassNames []string) error {
 		}
 	}
 
+	deleteErrors := NewMultiError(len(storageClassNames))
+
 	for _, storageClassName := range storageClassNames {
 		url := baseURL + "/storageclass/" + storageClassName
 
 		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
 		if err != nil {
-			return err
+			deleteErrors.Append(storageClassName, err)
+		} else if response.StatusCode == http.StatusNotFound && successOnNoop {
+			log.Debugf("Storage class %s not found, nothing to delete.", storageClassName)
 		} else if response.StatusCode != http.StatusOK {
-			return fmt.Errorf("could not delete storage class %s: %v", storageClassName,
-				GetErrorFromHTTPResponse(response, responseBody))
+			deleteErrors.AppendAction(storageClassName, "delete storage class", GetErrorFromHTTPResponse(response, responseBody))
 		}
 	}
 
-	return nil
+	return deleteErrors.ErrorOrNil()
 }
@@ -4,10 +4,10 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/netapp/trident/cli/api"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -19,16 +19,17 @@ func init() {
 }
 
 var deleteVolumeCmd = &cobra.Command{
-	Use:     "volume <name> [<name>...]",
-	Short:   "Delete one or more storage volumes from Trident",
-	Aliases: []string{"v", "volumes"},
+	Use:         "volume <name> [<name>...]",
+	Short:       "Delete one or more storage volumes from Trident",
+	Aliases:     []string{"v", "volumes"},
+	Annotations: map[string]string{annotationREST: "DELETE /volume/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"delete", "volume"}
 			if AllVolumes {
 				command = append(command, "--all")
 			}
-			TunnelCommand(append(command, args...))
+			TunnelCommand(append(command, args...), getDeletePodFlags()...)
 			return nil
 		} else {
 			return volumeDelete(args)
@@ -61,17 +62,20 @@ func volumeDelete(volumeNames []string) error {
 		}
 	}
 
+	deleteErrors := NewMultiError(len(volumeNames))
+
 	for _, volumeName := range volumeNames {
 		url := baseURL + "/volume/" + volumeName
 
 		response, responseBody, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
 		if err != nil {
-			return err
+			deleteErrors.Append(volumeName, err)
+		} else if response.StatusCode == http.StatusNotFound && successOnNoop {
+			log.Debugf("Volume %s not found, nothing to delete.", volumeName)
 		} else if response.StatusCode != http.StatusOK {
-			return fmt.Errorf("could not delete volume %s: %v", volumeName,
-				GetErrorFromHTTPResponse(response, responseBody))
+			deleteErrors.AppendAction(volumeName, "delete volume", GetErrorFromHTTPResponse(response, responseBody))
 		}
 	}
 
-	return nil
+	return deleteErrors.ErrorOrNil()
 }
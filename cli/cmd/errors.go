@@ -0,0 +1,178 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+// itemError associates an error with the resource that caused it.
+type itemError struct {
+	item string
+	err  error
+
+	// action, if set, is what failed for the item, e.g. "delete backend"
+	action string
+}
+
+// MultiError aggregates the per-item errors from a command that operates on several resources,
+// so that one failure doesn't prevent the remaining items from being processed.
+type MultiError struct {
+	total  int
+	errors []itemError
+}
+
+// NewMultiError returns an empty MultiError for an operation spanning the specified number of items.
+func NewMultiError(total int) *MultiError {
+	return &MultiError{total: total}
+}
+
+// Append records the error for a single item.
+func (e *MultiError) Append(item string, err error) {
+	e.errors = append(e.errors, itemError{item: item, err: err})
+}
+
+// AppendAction records the error for a single item that failed an action, e.g. "delete backend".  The
+// listing of several errors already names the item, so only a lone error names the action and item.
+func (e *MultiError) AppendAction(item, action string, err error) {
+	e.errors = append(e.errors, itemError{item: item, err: err, action: action})
+}
+
+// ErrorOrNil returns nil if no errors were recorded, the lone error if the operation only
+// spanned a single item, or the MultiError itself otherwise.
+func (e *MultiError) ErrorOrNil() error {
+	switch {
+	case len(e.errors) == 0:
+		return nil
+	case e.total <= 1 && len(e.errors) == 1:
+		if itemErr := e.errors[0]; itemErr.action != "" {
+			return fmt.Errorf("could not %s %s: %v", itemErr.action, itemErr.item, itemErr.err)
+		}
+		return e.errors[0].err
+	default:
+		return e
+	}
+}
+
+// Partial returns true if at least one item of the operation succeeded.
+func (e *MultiError) Partial() bool {
+	return len(e.errors) < e.total
+}
+
+func (e *MultiError) Error() string {
+
+	lines := []string{fmt.Sprintf("%d of %d operations failed:", len(e.errors), e.total)}
+	for _, itemErr := range e.errors {
+		lines = append(lines, fmt.Sprintf("  %s: %v", itemErr.item, itemErr.err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *MultiError) MarshalJSON() ([]byte, error) {
+
+	type jsonItemError struct {
+		Item  string `json:"item"`
+		Error string `json:"error"`
+	}
+
+	errors := make([]jsonItemError, 0, len(e.errors))
+	for _, itemErr := range e.errors {
+		errors = append(errors, jsonItemError{Item: itemErr.item, Error: itemErr.err.Error()})
+	}
+	return json.Marshal(errors)
+}
+
+// notFoundError is returned when no Trident pod could be found, so that scripts can tell from the
+// exit code that Trident isn't installed rather than that an operation failed.
+type notFoundError struct {
+	err error
+}
+
+func (e *notFoundError) Error() string {
+	return e.err.Error()
+}
+
+// timeoutError is returned when a command was stopped because it ran out of time.
+type timeoutError struct {
+	err error
+}
+
+func (e *timeoutError) Error() string {
+	return e.err.Error()
+}
+
+// tunnelError is returned when a command run in the Trident pod failed, having already written the
+// reason to stderr.
+type tunnelError struct {
+	err error
+}
+
+func (e *tunnelError) Error() string {
+	return e.err.Error()
+}
+
+// isDryRun returns whether an error only reports that --dry-run kept the REST requests of a command
+// from being sent.
+func isDryRun(err error) bool {
+
+	if multiError, ok := err.(*MultiError); ok {
+		for _, itemErr := range multiError.errors {
+			if itemErr.err != api.ErrDryRun {
+				return false
+			}
+		}
+		return len(multiError.errors) > 0
+	}
+
+	return err == api.ErrDryRun
+}
+
+// WriteError writes the error returned by a command to stderr.  If a structured output format was
+// requested, the error is written in that format too, so that scripts parsing the output needn't
+// handle plain text: aggregated errors as a list of per-item errors, and others as {"error": "..."}.
+// Errors remain plain text if the format was chosen automatically.
+func WriteError(err error) {
+
+	// A request not sent because of --dry-run is the expected outcome, not a failure
+	if isDryRun(err) {
+		return
+	}
+
+	defer writeFailureContext()
+
+	if commandTimedOut() {
+		err = commandTimeoutError()
+	} else if _, ok := err.(*tunnelError); ok {
+		// The command in the Trident pod explained the failure on stderr
+		return
+	}
+
+	format := OutputFormat
+	if autoOutputFormat {
+		format = ""
+	}
+
+	var structuredErr interface{} = map[string]string{"error": err.Error()}
+	if multiErr, ok := err.(*MultiError); ok {
+		structuredErr = multiErr
+	}
+
+	switch format {
+	case FormatJSON:
+		jsonBytes, _ := json.MarshalIndent(structuredErr, "", "  ")
+		fmt.Fprintln(os.Stderr, string(jsonBytes))
+	case FormatYAML:
+		jsonBytes, _ := json.Marshal(structuredErr)
+		yamlBytes, _ := yaml.JSONToYAML(jsonBytes)
+		fmt.Fprintln(os.Stderr, string(yamlBytes))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
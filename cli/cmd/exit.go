@@ -0,0 +1,22 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"os"
+	"sync"
+)
+
+var exitOnce sync.Once
+
+// Exit stops any port-forward, saves or shows the captured output, records the command in the audit
+// log, and exits.  It is called when the command completes, and when tridentctl is interrupted or
+// times out, so whichever comes first is recorded and any later call just waits for the exit.
+func Exit(exitCode int) {
+	exitOnce.Do(func() {
+		ExitCode = exitCode
+		StopPortForward()
+		FinishOutput(ExitCode)
+		os.Exit(ExitCode)
+	})
+}
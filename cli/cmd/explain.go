@@ -0,0 +1,114 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// annotationREST is the REST call made by a command, e.g. "GET /backend/{name}"
+	annotationREST = "rest"
+	// annotationMutates marks commands that change state without a REST call, e.g. install
+	annotationMutates = "mutates"
+
+	restNamePlaceholder = "{name}"
+)
+
+var Explain bool
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&Explain, "explain", false,
+		"Describe what the command would do instead of doing it")
+}
+
+// explainCommand replaces the action of a command with a description of what it would have done.
+// Discovery has already run by this point, so the description names the actual target.
+func explainCommand(cmd *cobra.Command) {
+	cmd.Run = nil
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return writeExplanation(cmd, args)
+	}
+}
+
+func writeExplanation(cmd *cobra.Command, args []string) error {
+
+	fmt.Printf("tridentctl %s would:\n", strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()+" "))
+
+	method, paths := getRESTCalls(cmd, args)
+
+	switch {
+	case cmd.Annotations[annotationMutates] == "true":
+		fmt.Println("  - run locally against the current Kubernetes cluster")
+	case skipDiscovery(cmd) || OperatingMode == "":
+		fmt.Println("  - run locally, without connecting to Trident")
+	case OperatingMode == ModeDirect:
+		fmt.Printf("  - connect directly to the Trident REST interface at %s\n", Server)
+	case OperatingMode == ModeTunnel:
+		context, err := getCurrentContext()
+		if err != nil {
+			context = "<unknown>"
+		}
+		fmt.Printf("  - target Trident pod %s in namespace %s (Kubernetes context %s)\n",
+			TridentPodName, TridentPodNamespace, context)
+		if method != "" {
+			fmt.Printf("  - run tridentctl in its %s container with '%s exec'\n",
+				TridentContainer, KubernetesCLI)
+		}
+	}
+
+	if method != "" && OperatingMode != "" && !skipDiscovery(cmd) {
+		baseURL, err := GetBaseURL()
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Printf("  - call %s %s%s\n", method, baseURL, path)
+		}
+	}
+
+	if (method != "" && method != http.MethodGet) || cmd.Annotations[annotationMutates] == "true" {
+		fmt.Println("  - change state (this command is not read-only)")
+	} else {
+		fmt.Println("  - not change any state (this command is read-only)")
+	}
+
+	return nil
+}
+
+// getRESTCalls returns the method and paths of the REST calls made by a command.  A path ending with
+// the name placeholder is called once per argument, or without the name to list every object if there
+// are no arguments; elsewhere in a path the placeholder stands for the first argument.
+func getRESTCalls(cmd *cobra.Command, args []string) (string, []string) {
+
+	rest, ok := cmd.Annotations[annotationREST]
+	if !ok {
+		return "", nil
+	}
+
+	parts := strings.SplitN(rest, " ", 2)
+	if len(parts) != 2 {
+		return "", nil
+	}
+	method, path := parts[0], parts[1]
+
+	if strings.HasSuffix(path, "/"+restNamePlaceholder) {
+		if len(args) == 0 {
+			return method, []string{strings.TrimSuffix(path, "/"+restNamePlaceholder)}
+		}
+		paths := make([]string, 0, len(args))
+		for _, arg := range args {
+			paths = append(paths, strings.Replace(path, restNamePlaceholder, arg, 1))
+		}
+		return method, paths
+	}
+
+	if len(args) > 0 {
+		path = strings.Replace(path, restNamePlaceholder, args[0], 1)
+	}
+	return method, []string{path}
+}
@@ -5,6 +5,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
@@ -15,22 +16,66 @@ func init() {
 }
 
 var getCmd = &cobra.Command{
-	Use:   "get",
-	Short: "Get one or more resources from Trident",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		err := discoverOperatingMode(cmd)
-		return err
-	},
+	Use:         "get",
+	Short:       "Get one or more resources from Trident",
+	Annotations: map[string]string{annotationNamespacedArgs: "true"},
 }
 
 func WriteJSON(out interface{}) {
 
-	jsonBytes, _ := json.MarshalIndent(out, "", "  ")
+	if AnnotateSource {
+		out = annotateSource(out)
+	}
+	if Redact {
+		out = redactSensitiveFields(out)
+	}
+	var jsonBytes []byte
+	if autoOutputFormat {
+		jsonBytes, _ = json.Marshal(out)
+	} else {
+		jsonBytes, _ = json.MarshalIndent(out, "", "  ")
+	}
 	fmt.Println(string(jsonBytes))
 }
 
+// WriteJSONPath writes the result of the --output JSONPath expression applied to the JSON representation
+// of an object, so field names are those of the REST interface.
+func WriteJSONPath(out interface{}) {
+
+	if AnnotateSource {
+		out = annotateSource(out)
+	}
+	if Redact {
+		out = redactSensitiveFields(out)
+	}
+	jsonBytes, err := json.Marshal(out)
+	if err != nil {
+		WriteError(err)
+		SetExitCodeFromError(err)
+		return
+	}
+	var value interface{}
+	if err = json.Unmarshal(jsonBytes, &value); err != nil {
+		WriteError(err)
+		SetExitCodeFromError(err)
+		return
+	}
+	if err = jsonPathTemplate.Execute(os.Stdout, value); err != nil {
+		WriteError(err)
+		SetExitCodeFromError(err)
+		return
+	}
+	fmt.Println()
+}
+
 func WriteYAML(out interface{}) {
 
+	if AnnotateSource {
+		out = annotateSource(out)
+	}
+	if Redact {
+		out = redactSensitiveFields(out)
+	}
 	jsonBytes, _ := json.Marshal(out)
 	yamlBytes, _ := yaml.JSONToYAML(jsonBytes)
 	fmt.Println(string(yamlBytes))
@@ -6,14 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
 
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -22,13 +20,18 @@ func init() {
 }
 
 var getBackendCmd = &cobra.Command{
-	Use:     "backend [<name>...]",
-	Short:   "Get one or more storage backends from Trident",
-	Aliases: []string{"b", "backends"},
+	Use:         "backend [<name>...]",
+	Short:       "Get one or more storage backends from Trident",
+	Aliases:     []string{"b", "backends"},
+	Annotations: map[string]string{annotationREST: "GET /backend/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"get", "backend"}
-			TunnelCommand(append(command, args...))
+			var backends api.MultipleBackendResponse
+			if err := TunnelCommandJSON(append(command, args...), &backends); err != nil {
+				return err
+			}
+			WriteBackends(backends.Items)
 			return nil
 		} else {
 			return backendList(args)
@@ -111,9 +114,29 @@ func GetBackend(baseURL, backendName string) (storage.BackendExternal, error) {
 }
 
 func WriteBackends(backends []storage.BackendExternal) {
+	if GetField != "" {
+		WriteItemField(backendItems(backends))
+		return
+	}
+	if OutputFormat == FormatCloudEvents {
+		WriteCloudEvents("backend", backendItems(backends))
+		return
+	}
+	if OutputFormat == FormatCount || CountBy != "" {
+		WriteItemCount(backendItems(backends))
+		return
+	}
+	if GroupBy != "" {
+		writeItemGroups(backendItems(backends), wrapBackends,
+			func(group interface{}) { writeBackendNames(group.(api.MultipleBackendResponse).Items) },
+			func(group interface{}) { writeBackendTable(group.(api.MultipleBackendResponse).Items) })
+		return
+	}
 	switch OutputFormat {
 	case FormatJSON:
 		WriteJSON(api.MultipleBackendResponse{Items: backends})
+	case FormatJSONPath:
+		WriteJSONPath(api.MultipleBackendResponse{Items: backends})
 	case FormatYAML:
 		WriteYAML(api.MultipleBackendResponse{Items: backends})
 	case FormatName:
@@ -123,6 +146,24 @@ func WriteBackends(backends []storage.BackendExternal) {
 	}
 }
 
+// backendItems returns backends as generic items for field access, grouping, and counting.
+func backendItems(backends []storage.BackendExternal) []interface{} {
+	items := make([]interface{}, 0, len(backends))
+	for _, b := range backends {
+		items = append(items, b)
+	}
+	return items
+}
+
+// wrapBackends returns backend items as the response listing them.
+func wrapBackends(items []interface{}) interface{} {
+	backends := make([]storage.BackendExternal, 0, len(items))
+	for _, item := range items {
+		backends = append(backends, item.(storage.BackendExternal))
+	}
+	return api.MultipleBackendResponse{Items: backends}
+}
+
 func getESeriesStorageDriverConfig(configAsMap map[string]interface{}) (*drivers.ESeriesStorageDriverConfig, error) {
 	jsonBytes, marshalError := json.MarshalIndent(configAsMap, "", "  ")
 	if marshalError != nil {
@@ -181,7 +222,7 @@ func getSolidfireStorageDriverConfig(configAsMap map[string]interface{}) (*drive
 
 func writeBackendTable(backends []storage.BackendExternal) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Name", "Storage Driver", "State", "Volumes"})
 
 	for _, b := range backends {
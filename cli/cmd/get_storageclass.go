@@ -6,11 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -19,13 +17,18 @@ func init() {
 }
 
 var getStorageClassCmd = &cobra.Command{
-	Use:     "storageclass [<name>...]",
-	Short:   "Get one or more storage classes from Trident",
-	Aliases: []string{"sc", "storageclasses"},
+	Use:         "storageclass [<name>...]",
+	Short:       "Get one or more storage classes from Trident",
+	Aliases:     []string{"sc", "storageclasses"},
+	Annotations: map[string]string{annotationREST: "GET /storageclass/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"get", "storageclass"}
-			TunnelCommand(append(command, args...))
+			var storageClasses api.MultipleStorageClassResponse
+			if err := TunnelCommandJSON(append(command, args...), &storageClasses); err != nil {
+				return err
+			}
+			WriteStorageClasses(storageClasses.Items)
 			return nil
 		} else {
 			return storageClassList(args)
@@ -108,9 +111,29 @@ func GetStorageClass(baseURL, storageClassName string) (api.StorageClass, error)
 }
 
 func WriteStorageClasses(storageClasses []api.StorageClass) {
+	if GetField != "" {
+		WriteItemField(storageClassItems(storageClasses))
+		return
+	}
+	if OutputFormat == FormatCloudEvents {
+		WriteCloudEvents("storageclass", storageClassItems(storageClasses))
+		return
+	}
+	if OutputFormat == FormatCount || CountBy != "" {
+		WriteItemCount(storageClassItems(storageClasses))
+		return
+	}
+	if GroupBy != "" {
+		writeItemGroups(storageClassItems(storageClasses), wrapStorageClasses,
+			func(group interface{}) { writeStorageClassNames(group.(api.MultipleStorageClassResponse).Items) },
+			func(group interface{}) { writeStorageClassTable(group.(api.MultipleStorageClassResponse).Items) })
+		return
+	}
 	switch OutputFormat {
 	case FormatJSON:
 		WriteJSON(api.MultipleStorageClassResponse{Items: storageClasses})
+	case FormatJSONPath:
+		WriteJSONPath(api.MultipleStorageClassResponse{Items: storageClasses})
 	case FormatYAML:
 		WriteYAML(api.MultipleStorageClassResponse{Items: storageClasses})
 	case FormatName:
@@ -120,9 +143,27 @@ func WriteStorageClasses(storageClasses []api.StorageClass) {
 	}
 }
 
+// storageClassItems returns storage classes as generic items for field access, grouping, and counting.
+func storageClassItems(storageClasses []api.StorageClass) []interface{} {
+	items := make([]interface{}, 0, len(storageClasses))
+	for _, sc := range storageClasses {
+		items = append(items, sc)
+	}
+	return items
+}
+
+// wrapStorageClasses returns storage class items as the response listing them.
+func wrapStorageClasses(items []interface{}) interface{} {
+	storageClasses := make([]api.StorageClass, 0, len(items))
+	for _, item := range items {
+		storageClasses = append(storageClasses, item.(api.StorageClass))
+	}
+	return api.MultipleStorageClassResponse{Items: storageClasses}
+}
+
 func writeStorageClassTable(storageClasses []api.StorageClass) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Name"})
 
 	for _, sc := range storageClasses {
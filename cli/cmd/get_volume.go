@@ -6,29 +6,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
 
 	"github.com/dustin/go-humanize"
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/storage"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
+const (
+	unitsBinary  = "binary"
+	unitsDecimal = "decimal"
+)
+
+var (
+	rawBytes  bool
+	sizeUnits string
+)
+
 func init() {
 	getCmd.AddCommand(getVolumeCmd)
+	getVolumeCmd.Flags().BoolVar(&rawBytes, "bytes", false, "Show sizes in tables as raw byte counts")
+	getVolumeCmd.Flags().StringVar(&sizeUnits, "units", unitsBinary,
+		"Units of the sizes shown in tables. One of binary|decimal")
 }
 
 var getVolumeCmd = &cobra.Command{
-	Use:     "volume [<name>...]",
-	Short:   "Get one or more volumes from Trident",
-	Aliases: []string{"v", "volumes"},
+	Use:         "volume [<name>...]",
+	Short:       "Get one or more volumes from Trident",
+	Aliases:     []string{"v", "volumes"},
+	Annotations: map[string]string{annotationREST: "GET /volume/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if sizeUnits != unitsBinary && sizeUnits != unitsDecimal {
+			return fmt.Errorf("invalid units %s; expected binary or decimal", sizeUnits)
+		}
+
 		if OperatingMode == ModeTunnel {
+			// Sizes are formatted here, so --units and --bytes aren't passed along
 			command := []string{"get", "volume"}
-			TunnelCommand(append(command, args...))
+			var volumes api.MultipleVolumeResponse
+			if err := TunnelCommandJSON(append(command, args...), &volumes); err != nil {
+				return err
+			}
+			WriteVolumes(volumes.Items)
 			return nil
 		} else {
 			return volumeList(args)
@@ -111,9 +132,35 @@ func GetVolume(baseURL, volumeName string) (storage.VolumeExternal, error) {
 }
 
 func WriteVolumes(volumes []storage.VolumeExternal) {
+	if GetField != "" {
+		WriteItemField(volumeItems(volumes))
+		return
+	}
+	if OutputFormat == FormatCloudEvents {
+		WriteCloudEvents("volume", volumeItems(volumes))
+		return
+	}
+	if OutputFormat == FormatCount || CountBy != "" {
+		WriteItemCount(volumeItems(volumes))
+		return
+	}
+	if GroupBy != "" {
+		writeItemGroups(volumeItems(volumes), wrapVolumes,
+			func(group interface{}) { writeVolumeNames(group.(api.MultipleVolumeResponse).Items) },
+			func(group interface{}) {
+				if OutputFormat == FormatWide {
+					writeWideVolumeTable(group.(api.MultipleVolumeResponse).Items)
+				} else {
+					writeVolumeTable(group.(api.MultipleVolumeResponse).Items)
+				}
+			})
+		return
+	}
 	switch OutputFormat {
 	case FormatJSON:
 		WriteJSON(api.MultipleVolumeResponse{Items: volumes})
+	case FormatJSONPath:
+		WriteJSONPath(api.MultipleVolumeResponse{Items: volumes})
 	case FormatYAML:
 		WriteYAML(api.MultipleVolumeResponse{Items: volumes})
 	case FormatName:
@@ -125,18 +172,34 @@ func WriteVolumes(volumes []storage.VolumeExternal) {
 	}
 }
 
+// volumeItems returns volumes as generic items for field access, grouping, and counting.
+func volumeItems(volumes []storage.VolumeExternal) []interface{} {
+	items := make([]interface{}, 0, len(volumes))
+	for _, v := range volumes {
+		items = append(items, v)
+	}
+	return items
+}
+
+// wrapVolumes returns volume items as the response listing them.
+func wrapVolumes(items []interface{}) interface{} {
+	volumes := make([]storage.VolumeExternal, 0, len(items))
+	for _, item := range items {
+		volumes = append(volumes, item.(storage.VolumeExternal))
+	}
+	return api.MultipleVolumeResponse{Items: volumes}
+}
+
 func writeVolumeTable(volumes []storage.VolumeExternal) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Name", "Size", "Storage Class", "Protocol", "Backend", "Pool"})
 
 	for _, volume := range volumes {
 
-		volumeSize, _ := strconv.ParseUint(volume.Config.Size, 10, 64)
-
 		table.Append([]string{
 			volume.Config.Name,
-			humanize.IBytes(volumeSize),
+			formatVolumeSize(volume.Config.Size),
 			volume.Config.StorageClass,
 			string(volume.Config.Protocol),
 			volume.Backend,
@@ -149,7 +212,7 @@ func writeVolumeTable(volumes []storage.VolumeExternal) {
 
 func writeWideVolumeTable(volumes []storage.VolumeExternal) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	header := []string{
 		"Name",
 		"Internal Name",
@@ -164,12 +227,10 @@ func writeWideVolumeTable(volumes []storage.VolumeExternal) {
 
 	for _, volume := range volumes {
 
-		volumeSize, _ := strconv.ParseUint(volume.Config.Size, 10, 64)
-
 		table.Append([]string{
 			volume.Config.Name,
 			volume.Config.InternalName,
-			humanize.IBytes(volumeSize),
+			formatVolumeSize(volume.Config.Size),
 			volume.Config.StorageClass,
 			string(volume.Config.Protocol),
 			volume.Backend,
@@ -187,3 +248,18 @@ func writeVolumeNames(volumes []storage.VolumeExternal) {
 		fmt.Println(sc.Config.Name)
 	}
 }
+
+// formatVolumeSize renders a volume size for table output, in binary (GiB) or decimal (GB) units,
+// or as raw bytes with --bytes.  Machine-readable formats always contain the raw size.
+func formatVolumeSize(size string) string {
+
+	if rawBytes {
+		return size
+	}
+
+	volumeSize, _ := strconv.ParseUint(size, 10, 64)
+	if sizeUnits == unitsDecimal {
+		return humanize.Bytes(volumeSize)
+	}
+	return humanize.IBytes(volumeSize)
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+
+	k8s "k8s.io/api/core/v1"
+)
+
+const HelmReleaseLabelKey = "app.kubernetes.io/instance"
+
+var HelmRelease string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&HelmRelease, "helm-release", "",
+		"Name of the Helm release that installed Trident, used to find the Trident pod and namespace")
+}
+
+// getHelmReleasePod returns the Trident pod belonging to a Helm release.  The release label alone
+// also matches other pods in the release, so it is combined with the Trident pod labels.  If no
+// namespace is specified, all namespaces are searched.
+func getHelmReleasePod(release, namespace string) (*k8s.Pod, error) {
+
+	releaseLabel := HelmReleaseLabelKey + "=" + release
+
+	appLabels := []string{TridentLabel, TridentCSILabel}
+	if CSI {
+		appLabels = []string{TridentCSILabel}
+	}
+
+	for _, appLabel := range appLabels {
+
+		pods, err := listRunningPods(namespace, releaseLabel+","+appLabel)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pods.Items) == 1 {
+			return &pods.Items[0], nil
+		} else if len(pods.Items) > 1 {
+			return nil, fmt.Errorf("found %d Trident pods in Helm release %s", len(pods.Items), release)
+		}
+	}
+
+	return nil, fmt.Errorf("could not find a Trident pod in Helm release %s", release)
+}
@@ -121,9 +121,14 @@ func init() {
 }
 
 var installCmd = &cobra.Command{
-	Use:   "install",
-	Short: "Install Trident",
+	Use:         "install",
+	Short:       "Install Trident",
+	Annotations: map[string]string{annotationMutates: "true"},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if Explain {
+			explainCommand(cmd)
+			return
+		}
 
 		initInstallerLogging()
 
@@ -167,16 +172,25 @@ var installCmd = &cobra.Command{
 	},
 }
 
-// initInstallerLogging configures logging for Trident installation. Logs are written to stdout.
+// initInstallerLogging configures logging for Trident installation. Logs are written to stdout
+// unless another log destination was requested.
 func initInstallerLogging() {
 
-	// Installer logs to stdout only
-	log.SetOutput(os.Stdout)
+	// Installer logs to stdout by default
+	if LogDestination == LogDestinationStderr {
+		log.SetOutput(os.Stdout)
+	} else {
+		log.SetOutput(diagnosticOutput)
+	}
 	log.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
 
 	logLevel := "info"
-	if silent {
+	if LogLevel != "" {
+		logLevel = LogLevel
+	} else if silent {
 		logLevel = "fatal"
+	} else if Quiet {
+		logLevel = "warn"
 	}
 	err := logging.InitLogLevel(Debug, logLevel)
 	if err != nil {
@@ -314,6 +328,10 @@ func validateInstallationArguments() error {
 	subdomainFormat := "a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', " +
 		"and must start and end with an alphanumeric character"
 
+	if TridentPodNamespace == namespaceAll {
+		return fmt.Errorf("'-n %s' searches all namespaces for an installed Trident, so it can't be "+
+			"used to choose where to install Trident; specify a single namespace", namespaceAll)
+	}
 	if !dns1123LabelRegex.MatchString(TridentPodNamespace) {
 		return fmt.Errorf("'%s' is not a valid namespace name; %s", TridentPodNamespace, labelFormat)
 	}
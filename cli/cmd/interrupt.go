@@ -0,0 +1,47 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// interruptContext is cancelled when tridentctl receives SIGINT or SIGTERM
+	interruptContext, cancelInterrupt = context.WithCancel(context.Background())
+
+	// tunnelsRunning counts the tunneled commands that will stop themselves if interrupted
+	tunnelsRunning int32
+
+	errInterrupted = errors.New("interrupted")
+)
+
+func init() {
+	cobra.OnInitialize(handleInterrupts)
+}
+
+// handleInterrupts cancels the interrupt context on SIGINT or SIGTERM, so that a tunneled command
+// kills its Kubernetes CLI process rather than leaving it behind.  With no tunneled command running,
+// tridentctl exits right away, though still through Exit so that the interrupted command is recorded.
+// A second signal is not caught.
+func handleInterrupts() {
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-signals
+		signal.Stop(signals)
+		cancelInterrupt()
+		if atomic.LoadInt32(&tunnelsRunning) == 0 {
+			Exit(ExitCodeInterrupted)
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+var (
+	KubernetesCLIName string
+	Kubeconfig        string
+	KubernetesContext string
+	KubeUser          string
+	KubeCluster       string
+	KubeAs            string
+	KubeAsGroups      []string
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&KubernetesCLIName, "k8s-cli", "",
+		"Kubernetes CLI to use instead of detecting it. One of kubectl|oc")
+	RootCmd.PersistentFlags().StringVar(&Kubeconfig, "kubeconfig", "",
+		"Path of the kubeconfig file used by the Kubernetes CLI")
+	RootCmd.PersistentFlags().StringVar(&KubernetesContext, "context", os.Getenv("TRIDENT_CONTEXT"),
+		"Kubeconfig context used by the Kubernetes CLI instead of the current context (env TRIDENT_CONTEXT)")
+	RootCmd.PersistentFlags().StringVar(&KubeUser, "kube-user", "",
+		"Kubeconfig user used by the Kubernetes CLI, overriding the user of the current context")
+	RootCmd.PersistentFlags().StringVar(&KubeCluster, "kube-cluster", "",
+		"Kubeconfig cluster used by the Kubernetes CLI, overriding the cluster of the current context")
+	RootCmd.PersistentFlags().StringVar(&KubeAs, "as", "",
+		"User to impersonate in commands sent to the Kubernetes cluster")
+	RootCmd.PersistentFlags().StringArrayVar(&KubeAsGroups, "as-group", nil,
+		"Group to impersonate in commands sent to the Kubernetes cluster (may be repeated)")
+}
+
+// kubectlCommand returns a command invoking the Kubernetes CLI with the specified arguments, preceded
+// by any global options that select how the CLI connects to the cluster.  All commands sent to the
+// cluster should be built with this so that those options apply consistently.  The command is killed
+// if tridentctl times out.
+func kubectlCommand(args ...string) *exec.Cmd {
+	return kubectlCommandContext(commandContext, args...)
+}
+
+// kubectlCommandContext is like kubectlCommand, but the command is killed if the context is done
+// before it completes.
+func kubectlCommandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, KubernetesCLI, kubectlArgs(args)...)
+	printCommand(cmd)
+	return cmd
+}
+
+// kubectlArgs prepends the global Kubernetes CLI options to the specified arguments.
+func kubectlArgs(args []string) []string {
+
+	var globalArgs []string
+	if Kubeconfig != "" {
+		globalArgs = append(globalArgs, "--kubeconfig", Kubeconfig)
+	}
+	if KubernetesContext != "" {
+		globalArgs = append(globalArgs, "--context", KubernetesContext)
+	}
+	if KubeUser != "" {
+		globalArgs = append(globalArgs, "--user", KubeUser)
+	}
+	if KubeCluster != "" {
+		globalArgs = append(globalArgs, "--cluster", KubeCluster)
+	}
+	if KubeAs != "" {
+		globalArgs = append(globalArgs, "--as="+KubeAs)
+	}
+	for _, group := range KubeAsGroups {
+		globalArgs = append(globalArgs, "--as-group="+group)
+	}
+
+	return append(globalArgs, args...)
+}
+
+// validateKubeconfig checks that the kubeconfig file specified with --kubeconfig exists, since the
+// Kubernetes CLI would otherwise fail with a less obvious error on every command.
+func validateKubeconfig() error {
+
+	if Kubeconfig == "" {
+		return nil
+	}
+
+	info, err := os.Stat(Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("could not read kubeconfig file %s; %v", Kubeconfig, err)
+	} else if info.IsDir() {
+		return fmt.Errorf("kubeconfig %s is a directory, not a file", Kubeconfig)
+	}
+
+	return nil
+}
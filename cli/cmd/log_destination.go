@@ -0,0 +1,91 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/logging"
+)
+
+const (
+	LogDestinationStderr = "stderr"
+	LogDestinationSyslog = "syslog"
+	LogDestinationFile   = "file"
+
+	syslogTag = "tridentctl"
+
+	DefaultLogLevel = "warn"
+)
+
+var (
+	LogDestination string
+	LogFile        string
+	LogLevel       string
+
+	// diagnosticOutput receives diagnostic messages, while results are always written to stdout
+	diagnosticOutput io.Writer = os.Stderr
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&LogDestination, "log-destination", LogDestinationStderr,
+		"Where to send diagnostic logs. One of stderr|syslog|file")
+	RootCmd.PersistentFlags().StringVar(&LogFile, "log-file", "",
+		"File to which diagnostic logs are appended when --log-destination=file")
+	RootCmd.PersistentFlags().StringVar(&LogLevel, "log-level", "",
+		"Level of diagnostic logs. One of debug|info|warn|error (default warn, or debug with --debug)")
+	cobra.OnInitialize(initLogDestination)
+}
+
+// initLogDestination opens the requested log destination.  Since tridentctl is often run unattended
+// when logging elsewhere, an unusable destination falls back to stderr rather than failing.
+func initLogDestination() {
+
+	var err error
+
+	switch LogDestination {
+	case LogDestinationStderr:
+		diagnosticOutput = os.Stderr
+	case LogDestinationSyslog:
+		diagnosticOutput, err = openSyslog(syslogTag)
+	case LogDestinationFile:
+		if LogFile == "" {
+			err = fmt.Errorf("--log-file must be specified with --log-destination=%s", LogDestinationFile)
+		} else {
+			diagnosticOutput, err = os.OpenFile(LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		}
+	default:
+		err = fmt.Errorf("unknown log destination %s", LogDestination)
+	}
+
+	if err != nil {
+		diagnosticOutput = os.Stderr
+		writeWarning("could not log to %s, logging to stderr instead; %v", LogDestination, err)
+	}
+
+	initLogging()
+}
+
+// initLogging sends leveled logs to the diagnostic output, so that stdout stays clean for results.
+// The installer reconfigures logging for itself, since its progress messages are its output.
+func initLogging() {
+
+	log.SetOutput(diagnosticOutput)
+	api.DebugOutput = diagnosticOutput
+	log.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
+
+	logLevel := LogLevel
+	if logLevel == "" {
+		logLevel = DefaultLogLevel
+	}
+	if err := logging.InitLogLevel(Debug, logLevel); err != nil {
+		writeWarning("invalid log level %s, using %s instead; %v", LogLevel, DefaultLogLevel, err)
+		log.SetLevel(log.WarnLevel)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+//go:build windows || nacl || plan9
+// +build windows nacl plan9
+
+package cmd
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog(tag string) (io.Writer, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
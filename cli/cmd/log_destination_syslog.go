@@ -0,0 +1,15 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package cmd
+
+import (
+	"io"
+	"log/syslog"
+)
+
+func openSyslog(tag string) (io.Writer, error) {
+	return syslog.New(syslog.LOG_INFO|syslog.LOG_USER, tag)
+}
@@ -4,14 +4,16 @@ package cmd
 
 import (
 	"archive/zip"
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/netapp/trident/config"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -32,9 +34,11 @@ const (
 )
 
 var (
-	logType  string
-	archive  bool
-	previous bool
+	logType       string
+	archive       bool
+	previous      bool
+	interleave    bool
+	allContainers bool
 )
 
 func init() {
@@ -42,22 +46,26 @@ func init() {
 	logsCmd.Flags().StringVarP(&logType, "log", "l", logTypeAuto, "Trident log to display. One of trident|etcd|auto|all")
 	logsCmd.Flags().BoolVarP(&archive, "archive", "a", false, "Create a support archive with all logs unless otherwise specified.")
 	logsCmd.Flags().BoolVarP(&previous, "previous", "p", false, "Get the logs for the previous container instance if it exists.")
+	logsCmd.Flags().BoolVar(&interleave, "interleave", false, "Merge the lines of multiple logs in timestamp order.")
+	logsCmd.Flags().BoolVar(&allContainers, "all-containers", false,
+		"Get the logs of every container in the Trident pod, such as the CSI sidecars.")
 }
 
 var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Print the logs from Trident",
 	Long:  "Print the logs from the Trident storage orchestrator for Kubernetes",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		err := discoverOperatingMode(cmd)
-		return err
-	},
+	// Logs are read with the Kubernetes CLI rather than by tunneling, so only pods/log access is needed
+	Annotations: map[string]string{annotationNoExec: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		err := checkValidLog()
 		if err != nil {
 			return err
 		}
+		if allContainers && cmd.Flags().Changed("log") {
+			return errors.New("--all-containers may not be combined with --log")
+		}
 
 		if archive {
 			return archiveLogs()
@@ -76,7 +84,7 @@ func archiveLogs() error {
 	}
 
 	logMap := make(map[string][]byte)
-	getLogs(logMap)
+	logsErr := getLogs(logMap)
 
 	// If there aren't any logs, bail out.
 	anyLogs := false
@@ -114,6 +122,10 @@ func archiveLogs() error {
 		fmt.Printf("Wrote %s log to %s archive file.\n", log, filename)
 	}
 
+	// Any logs that couldn't be archived still fail the command
+	if allContainers {
+		return logsErr
+	}
 	return nil
 }
 
@@ -123,6 +135,13 @@ func consoleLogs() error {
 	err := getLogs(logMap)
 
 	SetExitCodeFromError(err)
+
+	// Print the logs of the other containers even if some couldn't be read
+	if multiError, ok := err.(*MultiError); ok && multiError.Partial() {
+		printLogs(logMap)
+		return err
+	}
+
 	if err != nil {
 		// Preserve anything written to stdout/stderr
 		logMessage := strings.TrimSuffix(strings.TrimSpace(string(logMap["error"])), ".")
@@ -134,20 +153,42 @@ func consoleLogs() error {
 		}
 	}
 
-	// Print to the console
+	if interleave {
+		if interleavedLogs, ok := interleaveLogs(logMap); ok {
+			fmt.Print(interleavedLogs)
+			return nil
+		}
+		log.Debug("Log timestamps are unavailable, so the logs will not be interleaved.")
+	}
+
+	if !printLogs(logMap) {
+		return errors.New("no Trident-related logs found")
+	}
+
+	return nil
+}
+
+// printLogs prints the logs to the console, returning whether there were any.  The logs of all
+// containers are printed with each line prefixed by its container, as when they are interleaved.
+func printLogs(logMap map[string][]byte) bool {
+
 	anyLogs := false
 	for log, logBytes := range logMap {
-		if log != "error" {
+		if log == "error" {
+			continue
+		}
+		if allContainers {
+			for _, line := range strings.Split(strings.TrimSuffix(string(logBytes), "\n"), "\n") {
+				fmt.Printf("[%s] %s\n", log, line)
+			}
+		} else {
 			fmt.Printf("%s log:\n", log)
 			fmt.Printf("%s\n", string(logBytes))
-			anyLogs = true
 		}
-	}
-	if !anyLogs {
-		return errors.New("no Trident-related logs found")
+		anyLogs = true
 	}
 
-	return nil
+	return anyLogs
 }
 
 func getLogs(logMap map[string][]byte) error {
@@ -158,6 +199,14 @@ func getLogs(logMap map[string][]byte) error {
 		return errors.New("'tridentctl logs' only supports Trident running in a Kubernetes pod")
 	}
 
+	if err = refreshTridentPod(); err != nil {
+		return err
+	}
+
+	if allContainers {
+		return getAllContainerLogs(logMap)
+	}
+
 	switch logType {
 	case logTypeTrident, logTypeAuto:
 		err = getTridentLogs(logNameTrident, logMap)
@@ -199,9 +248,9 @@ func getTridentLogs(logName string, logMap map[string][]byte) error {
 
 	switch logName {
 	case logNameTrident:
-		container, prev = config.ContainerTrident, false
+		container, prev = TridentContainer, false
 	case logNameTridentPrevious:
-		container, prev = config.ContainerTrident, true
+		container, prev = TridentContainer, true
 	case logNameEtcd:
 		container, prev = config.ContainerEtcd, false
 	case logNameEtcdPrevious:
@@ -210,17 +259,12 @@ func getTridentLogs(logName string, logMap map[string][]byte) error {
 		return fmt.Errorf("%s is not a valid Trident log", logName)
 	}
 
-	// Build command to get K8S logs
-	limitArg := fmt.Sprintf("--limit-bytes=%d", LogLimitBytes)
-	prevArg := fmt.Sprintf("--previous=%v", prev)
-	logsCommand := []string{"logs", TridentPodName, "-n", TridentPodNamespace, "-c", container, limitArg, prevArg}
-
-	if Debug {
-		fmt.Printf("Invoking command: %s %v\n", KubernetesCLI, strings.Join(logsCommand, " "))
+	if err := checkTridentContainer(container); err != nil {
+		return err
 	}
 
 	// Get logs
-	logBytes, err := exec.Command(KubernetesCLI, logsCommand...).CombinedOutput()
+	logBytes, err := runLogsCommand(container, prev)
 	if err != nil {
 		logMap["error"] = appendError(logMap["error"], logBytes)
 	} else {
@@ -229,6 +273,97 @@ func getTridentLogs(logName string, logMap map[string][]byte) error {
 	return err
 }
 
+// getAllContainerLogs gets the logs of every container in the Trident pod, keyed by container name.
+// A failure to get the logs of one container doesn't prevent getting the others.  Previous logs exist
+// only for restarted containers, so failing to get them is not an error.
+func getAllContainerLogs(logMap map[string][]byte) error {
+
+	if len(tridentContainers) == 0 {
+		return fmt.Errorf("no containers found in Trident pod %s", TridentPodName)
+	}
+
+	logErrors := NewMultiError(len(tridentContainers))
+	for _, container := range tridentContainers {
+
+		logBytes, err := runLogsCommand(container, false)
+		if err != nil {
+			logErrors.Append(container, fmt.Errorf("%v; %s", err, strings.TrimSpace(string(logBytes))))
+		} else {
+			logMap[container] = logBytes
+		}
+
+		if previous {
+			if logBytes, err = runLogsCommand(container, true); err == nil {
+				logMap[container+"-previous"] = logBytes
+			}
+		}
+	}
+
+	return logErrors.ErrorOrNil()
+}
+
+// runLogsCommand returns the log of a container in the Trident pod, or the output of the command if
+// it fails.
+func runLogsCommand(container string, prev bool) ([]byte, error) {
+
+	// Build command to get K8S logs
+	limitArg := fmt.Sprintf("--limit-bytes=%d", LogLimitBytes)
+	prevArg := fmt.Sprintf("--previous=%v", prev)
+	logsCommand := []string{"logs", TridentPodName, "-n", TridentPodNamespace, "-c", container, limitArg, prevArg}
+	if interleave && !archive {
+		logsCommand = append(logsCommand, "--timestamps")
+	}
+
+	log.Debugf("Invoking command: %s %v", KubernetesCLI, strings.Join(logsCommand, " "))
+
+	return kubectlCommand(logsCommand...).CombinedOutput()
+}
+
+type logLine struct {
+	timestamp time.Time
+	logName   string
+	line      string
+}
+
+// interleaveLogs merges the lines of multiple logs in timestamp order, prefixing each line with the
+// name of its log.  It returns false if any line lacks a timestamp, in which case the logs should
+// be printed one after another.
+func interleaveLogs(logMap map[string][]byte) (string, bool) {
+
+	logNames := make([]string, 0, len(logMap))
+	for logName := range logMap {
+		if logName != "error" {
+			logNames = append(logNames, logName)
+		}
+	}
+	sort.Strings(logNames)
+
+	var lines []logLine
+	for _, logName := range logNames {
+		for _, line := range strings.Split(string(logMap[logName]), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			fields := strings.SplitN(line, " ", 2)
+			timestamp, err := time.Parse(time.RFC3339Nano, fields[0])
+			if err != nil || len(fields) != 2 {
+				return "", false
+			}
+			lines = append(lines, logLine{timestamp: timestamp, logName: logName, line: fields[1]})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].timestamp.Before(lines[j].timestamp) })
+
+	var interleavedLogs bytes.Buffer
+	for _, line := range lines {
+		fmt.Fprintf(&interleavedLogs, "%s [%s] %s\n",
+			line.timestamp.Format(time.RFC3339Nano), line.logName, line.line)
+	}
+
+	return interleavedLogs.String(), true
+}
+
 func appendError(oldErrors, newError []byte) []byte {
 
 	if len(oldErrors) == 0 {
@@ -0,0 +1,53 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var namespaceRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// applyNamespacedArgs accepts resource names of the form <namespace>/<name>, which select the Trident
+// namespace as if it were specified with -n.  The namespace prefixes are removed from the arguments
+// in place, so the command sees only the resource names.  All prefixes must agree with each other and
+// with any -n option.
+func applyNamespacedArgs(cmd *cobra.Command, args []string) error {
+
+	namespace := ""
+	if cmd.Flags().Changed("namespace") {
+		namespace = TridentPodNamespace
+	}
+
+	for i, arg := range args {
+
+		slash := strings.Index(arg, "/")
+		if slash < 0 {
+			continue
+		}
+
+		argNamespace, name := arg[:slash], arg[slash+1:]
+		if len(argNamespace) > 63 || !namespaceRegex.MatchString(argNamespace) {
+			return fmt.Errorf("invalid namespace %s in %s", argNamespace, arg)
+		} else if name == "" {
+			return fmt.Errorf("no resource name specified in %s", arg)
+		}
+
+		if namespace != "" && argNamespace != namespace {
+			return fmt.Errorf("namespace %s in %s conflicts with namespace %s", argNamespace, arg, namespace)
+		}
+
+		namespace = argNamespace
+		args[i] = name
+	}
+
+	if namespace != "" {
+		TridentPodNamespace = namespace
+	}
+
+	return nil
+}
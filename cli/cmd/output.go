@@ -0,0 +1,557 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	groupValueNone = "<none>"
+
+	DefaultSourceKeyPrefix = "__"
+)
+
+var (
+	// autoOutputFormat is set if the output format was chosen automatically
+	autoOutputFormat bool
+
+	// jsonPathExpression and jsonPathTemplate are parsed from --output=jsonpath=<expression>
+	jsonPathExpression string
+	jsonPathTemplate   *jsonpath.JSONPath
+
+	GroupBy  string
+	CountBy  string
+	GetField string
+
+	AnnotateSource  bool
+	SourceKeyPrefix string
+	SourceContext   string
+	SourceNamespace string
+)
+
+func init() {
+	getCmd.PersistentFlags().StringVar(&GroupBy, "group-by", "",
+		"Group listed items by the value of a field, specified as a dotted path (e.g. backend)")
+	getCmd.PersistentFlags().StringVar(&CountBy, "count-by", "",
+		"Count listed items by the value of a field, specified as a dotted path (e.g. state)")
+	getCmd.PersistentFlags().StringVar(&GetField, "get", "",
+		"Print only the value of a field of a single object as raw text, specified as a dotted path (e.g. state)")
+
+	RootCmd.PersistentFlags().BoolVar(&AnnotateSource, "annotate-source", false,
+		"Annotate results with the Kubernetes context and namespace they came from")
+	RootCmd.PersistentFlags().StringVar(&SourceKeyPrefix, "source-key-prefix", DefaultSourceKeyPrefix,
+		"Prefix of the context and namespace fields added to results by --annotate-source")
+	RootCmd.PersistentFlags().StringVar(&SourceContext, "source-context", "", "Context with which to annotate results")
+	RootCmd.PersistentFlags().StringVar(&SourceNamespace, "source-namespace", "",
+		"Namespace with which to annotate results")
+	RootCmd.PersistentFlags().MarkHidden("source-context")
+	RootCmd.PersistentFlags().MarkHidden("source-namespace")
+}
+
+// resolveOutputFormat replaces the auto output format with the format suited to where the output goes:
+// tables for a person at a terminal, and compact JSON for a program reading from a pipe.
+func resolveOutputFormat() {
+
+	if OutputFormat != FormatAuto {
+		return
+	}
+
+	autoOutputFormat = true
+	if stdoutIsTerminal() {
+		OutputFormat = ""
+	} else {
+		OutputFormat = FormatJSON
+	}
+}
+
+// parseJSONPathFormat splits an output format of the form jsonpath=<expression> into the format name
+// and a parsed JSONPath template, e.g. jsonpath='{.items[0].name}'.
+func parseJSONPathFormat() error {
+
+	if !strings.HasPrefix(OutputFormat, FormatJSONPath+"=") {
+		if OutputFormat == FormatJSONPath && jsonPathTemplate == nil {
+			return fmt.Errorf("output format %s requires an expression, e.g. %s='{.items[0].name}'",
+				FormatJSONPath, FormatJSONPath)
+		}
+		return nil
+	}
+
+	expression := strings.TrimPrefix(OutputFormat, FormatJSONPath+"=")
+	if len(expression) >= 2 && (expression[0] == '\'' || expression[0] == '"') &&
+		expression[len(expression)-1] == expression[0] {
+		expression = expression[1 : len(expression)-1]
+	}
+	if expression == "" {
+		return fmt.Errorf("output format %s requires an expression", FormatJSONPath)
+	}
+
+	template := jsonpath.New("output")
+	if err := template.Parse(expression); err != nil {
+		return fmt.Errorf("invalid JSONPath expression %s; %v", expression, err)
+	}
+
+	OutputFormat = FormatJSONPath
+	jsonPathExpression = expression
+	jsonPathTemplate = template
+	return nil
+}
+
+// tableOutput is implemented by results that may be written as a table by writeOutput.
+type tableOutput interface {
+	tableHeader(wide bool) []string
+	tableRows(wide bool) [][]string
+}
+
+// nameOutput is implemented by results that may be written as a list of names by writeOutput.
+type nameOutput interface {
+	names() []string
+}
+
+// writeOutput writes a result in the requested output format.  Results that can't be written as names
+// are written as a table instead, and those that can't be written as a table are written as YAML.
+func writeOutput(out interface{}) {
+
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(out)
+		return
+	case FormatJSONPath:
+		WriteJSONPath(out)
+		return
+	case FormatYAML:
+		WriteYAML(out)
+		return
+	case FormatName:
+		if namedOut, ok := out.(nameOutput); ok {
+			for _, name := range namedOut.names() {
+				fmt.Println(name)
+			}
+			return
+		}
+	}
+
+	tabularOut, ok := out.(tableOutput)
+	if !ok {
+		WriteYAML(out)
+		return
+	}
+	wide := OutputFormat == FormatWide
+	writeTable(tabularOut.tableHeader(wide), tabularOut.tableRows(wide), wide)
+}
+
+// getSource returns the Kubernetes context and namespace with which results are annotated.  In
+// direct mode there is no context, so the server address identifies the source instead.
+func getSource() (string, string) {
+
+	if SourceContext == "" {
+		if OperatingMode == ModeTunnel {
+			context, err := getCurrentContext()
+			if err != nil {
+				log.Debugf("Could not determine the source of the results; %v", err)
+			}
+			SourceContext = context
+		} else {
+			SourceContext = Server
+		}
+	}
+	if SourceNamespace == "" {
+		SourceNamespace = TridentPodNamespace
+	}
+
+	return SourceContext, SourceNamespace
+}
+
+// annotateSource returns the JSON representation of a result with the source context and namespace
+// added to the result object, or to each of its items if it is a list.
+func annotateSource(out interface{}) interface{} {
+
+	jsonBytes, err := json.Marshal(out)
+	if err != nil {
+		return out
+	}
+
+	var result interface{}
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return out
+	}
+
+	context, namespace := getSource()
+	annotate := func(value interface{}) {
+		if object, ok := value.(map[string]interface{}); ok {
+			object[SourceKeyPrefix+"context"] = context
+			object[SourceKeyPrefix+"namespace"] = namespace
+		}
+	}
+
+	if object, ok := result.(map[string]interface{}); ok {
+		if items, ok := object["items"].([]interface{}); ok {
+			for _, item := range items {
+				annotate(item)
+			}
+		} else {
+			annotate(object)
+		}
+	}
+
+	return result
+}
+
+// getFieldValue returns the value found at a dotted path (e.g. config.storageDriverName) within the
+// JSON representation of an object.  Field names are matched case-insensitively.
+func getFieldValue(obj interface{}, path string) (interface{}, error) {
+
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(jsonBytes, &value); err != nil {
+		return nil, err
+	}
+
+	for _, field := range strings.Split(path, ".") {
+
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field %s not found", path)
+		}
+
+		found := false
+		for name, fieldValue := range fields {
+			if strings.EqualFold(name, field) {
+				value, found = fieldValue, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("field %s not found", path)
+		}
+	}
+
+	return value, nil
+}
+
+// groupItems partitions a list of items by the value of the specified field.  It returns the distinct
+// values in order of first appearance, plus the indices of the items having each value.  Items lacking
+// the field are grouped under a placeholder value.
+func groupItems(items []interface{}, field string) ([]string, map[string][]int) {
+
+	var values []string
+	groups := make(map[string][]int)
+
+	for i, item := range items {
+
+		groupValue := groupValueNone
+		if value, err := getFieldValue(item, field); err == nil && value != nil {
+			groupValue = fmt.Sprintf("%v", value)
+		}
+
+		if _, ok := groups[groupValue]; !ok {
+			values = append(values, groupValue)
+		}
+		groups[groupValue] = append(groups[groupValue], i)
+	}
+
+	return values, groups
+}
+
+// writeItemGroups writes items grouped by the value of the --group-by field.  Structured formats map
+// each value to its items, which wrap returns as the response listing them, while text formats write
+// each group under a header with writeNames or writeTable.
+func writeItemGroups(items []interface{}, wrap func(group []interface{}) interface{},
+	writeNames, writeTable func(group interface{})) {
+
+	values, groups := groupItems(items, GroupBy)
+
+	groupedItems := make(map[string]interface{})
+	for value, indices := range groups {
+		group := make([]interface{}, 0, len(indices))
+		for _, i := range indices {
+			group = append(group, items[i])
+		}
+		groupedItems[value] = wrap(group)
+	}
+
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(groupedItems)
+	case FormatJSONPath:
+		WriteJSONPath(groupedItems)
+	case FormatYAML:
+		WriteYAML(groupedItems)
+	default:
+		for _, value := range values {
+			writeGroupHeader(value)
+			if OutputFormat == FormatName {
+				writeNames(groupedItems[value])
+			} else {
+				writeTable(groupedItems[value])
+			}
+		}
+	}
+}
+
+// WriteItemField writes the value of the --get field of a single item as raw text.
+func WriteItemField(items []interface{}) {
+
+	value, err := getScalarField(items, GetField)
+	if err != nil {
+		WriteError(err)
+		SetExitCodeFromError(err)
+		return
+	}
+
+	if Redact && value != "" && isRedactedFieldPath(GetField) {
+		value = redactedValue
+	}
+
+	fmt.Println(value)
+}
+
+// getScalarField returns the value of a field of a single item as raw text, or an error if there isn't
+// exactly one item or the field is missing or not scalar.
+func getScalarField(items []interface{}, field string) (string, error) {
+
+	if len(items) != 1 {
+		return "", fmt.Errorf("--get requires a single object, but found %d", len(items))
+	}
+
+	value, err := getFieldValue(items[0], field)
+	if err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("field %s is not a scalar value", field)
+	default:
+		jsonBytes, err := json.Marshal(v)
+		return string(jsonBytes), err
+	}
+}
+
+// ItemCount is the number of listed items, optionally broken down by the value of a field.
+type ItemCount struct {
+	Total   int
+	Field   string
+	ByValue map[string]int
+}
+
+// MarshalJSON names the breakdown after the counted field, e.g. {"total": 3, "byState": {...}}.
+func (c ItemCount) MarshalJSON() ([]byte, error) {
+
+	count := map[string]interface{}{"total": c.Total}
+	if c.Field != "" {
+		key := "by"
+		for _, segment := range strings.Split(c.Field, ".") {
+			if segment != "" {
+				key += strings.ToUpper(segment[:1]) + segment[1:]
+			}
+		}
+		count[key] = c.ByValue
+	}
+
+	return json.Marshal(count)
+}
+
+// countItems counts a list of items, broken down by the value of the --count-by field if specified.
+func countItems(items []interface{}) ItemCount {
+
+	count := ItemCount{Total: len(items), Field: CountBy}
+	if CountBy != "" {
+		count.ByValue = make(map[string]int)
+		_, groups := groupItems(items, CountBy)
+		for value, indices := range groups {
+			count.ByValue[value] = len(indices)
+		}
+	}
+
+	return count
+}
+
+func WriteItemCount(items []interface{}) {
+
+	count := countItems(items)
+
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(count)
+	case FormatJSONPath:
+		WriteJSONPath(count)
+	case FormatYAML:
+		WriteYAML(count)
+	default:
+		writeItemCountTable(count)
+	}
+}
+
+func writeItemCountTable(count ItemCount) {
+
+	if count.Field == "" {
+		fmt.Println(count.Total)
+		return
+	}
+
+	values := make([]string, 0, len(count.ByValue))
+	for value := range count.ByValue {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	table := newTableWriter()
+	table.SetHeader([]string{count.Field, "Count"})
+	for _, value := range values {
+		table.Append([]string{value, strconv.Itoa(count.ByValue[value])})
+	}
+	table.Render()
+}
+
+// FieldChange describes a field whose value differs between two versions of an object.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ObjectChanges lists the changed fields of a named object.
+type ObjectChanges struct {
+	Name    string        `json:"name"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// diffObjects compares the JSON representations of two versions of an object, returning
+// the fields that were added, removed, or modified, sorted by field path.
+func diffObjects(before, after interface{}) ([]FieldChange, error) {
+
+	beforeFields, err := flattenObject(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := flattenObject(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]FieldChange, 0)
+	for field, oldValue := range beforeFields {
+		if newValue, ok := afterFields[field]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	for field, newValue := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+// flattenObject returns the leaf values of an object's JSON representation keyed by dotted path.
+func flattenObject(obj interface{}) (map[string]interface{}, error) {
+
+	jsonBytes, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if err = json.Unmarshal(jsonBytes, &value); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	flattenValue("", value, fields)
+	return fields, nil
+}
+
+func flattenValue(path string, value interface{}, fields map[string]interface{}) {
+
+	if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+		for name, nestedValue := range nested {
+			if path == "" {
+				flattenValue(name, nestedValue, fields)
+			} else {
+				flattenValue(path+"."+name, nestedValue, fields)
+			}
+		}
+		return
+	}
+
+	fields[path] = value
+}
+
+func WriteObjectChanges(changes []ObjectChanges) {
+
+	// The values of a change aren't found under the field's name, so they are redacted by path
+	if Redact {
+		changes = redactObjectChanges(changes)
+	}
+
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(changes)
+	case FormatJSONPath:
+		WriteJSONPath(changes)
+	case FormatYAML:
+		WriteYAML(changes)
+	default:
+		writeObjectChangesTable(changes)
+	}
+}
+
+func writeObjectChangesTable(changes []ObjectChanges) {
+
+	table := newTableWriter()
+	table.SetHeader([]string{"Name", "Field", "Old Value", "New Value"})
+
+	for _, objectChanges := range changes {
+		for _, change := range objectChanges.Changes {
+			table.Append([]string{
+				objectChanges.Name,
+				change.Field,
+				formatFieldValue(change.Old),
+				formatFieldValue(change.New),
+			})
+		}
+	}
+
+	table.Render()
+}
+
+// formatFieldValue renders a field value for table output.
+func formatFieldValue(value interface{}) string {
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		jsonBytes, _ := json.Marshal(v)
+		return string(jsonBytes)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// writeGroupHeader writes the header preceding each group of items in text output.
+func writeGroupHeader(value string) {
+	fmt.Printf("%s: %s\n", GroupBy, value)
+}
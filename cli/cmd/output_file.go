@@ -0,0 +1,34 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var OutputFile string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&OutputFile, "output-file", "",
+		"Write the result to this file instead of stdout, creating its directory if needed")
+}
+
+// writeOutputFile saves the result of a command.  Results such as backend configurations may be
+// sensitive, so the file is readable by its owner only.
+func writeOutputFile(path string, result []byte) error {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create the directory of output file %s; %v", path, err)
+	}
+	if err := ioutil.WriteFile(path, result, 0600); err != nil {
+		return fmt.Errorf("could not write output file %s; %v", path, err)
+	}
+
+	log.Debugf("Wrote the result to %s.", path)
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// podFlag is an option passed along to the tridentctl in the Trident pod.  The pod runs the tridentctl
+// of its own Trident release, which may predate the option, so it is only passed along if that
+// tridentctl supports it.
+type podFlag struct {
+	args []string
+
+	// required is set for options the command must not run without, such as --dry-run
+	required bool
+}
+
+// requiredPodFlag returns an option without which the tunneled command must fail rather than run.
+func requiredPodFlag(args ...string) podFlag {
+	return podFlag{args: args, required: true}
+}
+
+// optionalPodFlag returns an option that is left out if the tridentctl in the pod doesn't support it.
+func optionalPodFlag(args ...string) podFlag {
+	return podFlag{args: args}
+}
+
+// podHelp caches the help of the tridentctl in the Trident pod by command
+var podHelp = make(map[string]string)
+
+// appendPodFlags appends the options supported by the tridentctl in the Trident pod, which are found
+// in its help for the command.  The help is only requested if there are options to check.
+func appendPodFlags(cliCommand, commandArgs []string, flags []podFlag) ([]string, error) {
+
+	if len(flags) == 0 {
+		return cliCommand, nil
+	}
+
+	help, err := getPodHelp(commandArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flag := range flags {
+		name := flag.args[0]
+		if podHelpListsFlag(help, name) {
+			cliCommand = append(cliCommand, flag.args...)
+		} else if flag.required {
+			return nil, fmt.Errorf("the tridentctl in the Trident pod does not support %s; upgrade Trident, "+
+				"or connect to Trident directly with --server", name)
+		} else {
+			log.Debugf("Leaving out %s, which the tridentctl in the Trident pod does not support.", name)
+		}
+	}
+
+	return cliCommand, nil
+}
+
+// getPodHelp returns the help of the tridentctl in the Trident pod for a command, which is named by the
+// arguments preceding any options.
+func getPodHelp(commandArgs []string) (string, error) {
+
+	var commandPath []string
+	for _, arg := range commandArgs {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		commandPath = append(commandPath, arg)
+	}
+
+	key := strings.Join(commandPath, " ")
+	if help, ok := podHelp[key]; ok {
+		return help, nil
+	}
+
+	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", TridentContainer, "--",
+		TunnelEntrypoint}
+	execCommand = append(execCommand, commandPath...)
+	execCommand = append(execCommand, "--help")
+
+	cmd := kubectlCommand(execCommand...)
+	output, err := cmd.Output()
+	var stderr []byte
+	if exitError, ok := err.(*exec.ExitError); ok {
+		stderr = exitError.Stderr
+	}
+	recordCommand(cmd, stderr, err)
+	if err != nil {
+		return "", fmt.Errorf("could not determine the options supported by the tridentctl in the Trident "+
+			"pod; %v", getDiscoveryError(err, stderr))
+	}
+
+	podHelp[key] = string(output)
+	return podHelp[key], nil
+}
+
+// podHelpListsFlag returns whether the help of a command lists an option, e.g. "--output" in
+// "  -o, --output string   Output format...", but not options merely mentioned in a description.
+func podHelpListsFlag(help, name string) bool {
+	return regexp.MustCompile(`(?m)^\s*(-\w, )?` + regexp.QuoteMeta(name) + `(\s|=|$)`).MatchString(help)
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodHelpListsFlag(t *testing.T) {
+
+	help := `Get one or more storage backends from Trident
+
+Usage:
+  tridentctl get backend [<name>...] [flags]
+
+Global Flags:
+  -d, --debug              Debug output
+      --dry-run            Print the requests to the Trident REST interface instead of sending them
+  -o, --output string      Output format. One of json|yaml|name|wide|ps (default)
+      --redact-fields strings   Names of the fields masked by --redact
+`
+
+	assert.True(t, podHelpListsFlag(help, "--debug"))
+	assert.True(t, podHelpListsFlag(help, "--dry-run"))
+	assert.True(t, podHelpListsFlag(help, "--output"))
+	assert.True(t, podHelpListsFlag(help, "--redact-fields"))
+	assert.False(t, podHelpListsFlag(help, "--redact"), "--redact is only mentioned in a description")
+	assert.False(t, podHelpListsFlag(help, "--api-version"))
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ServerServicePrefix = "svc:"
+
+	portForwardPrefix = "Forwarding from 127.0.0.1:"
+)
+
+var portForwardCmd *exec.Cmd
+
+// resolveServiceServer replaces a server of the form svc:<namespace>/<service>:<port> with the local
+// end of a port-forward to that service, so that the command may run in direct mode.
+func resolveServiceServer() error {
+
+	if !strings.HasPrefix(Server, ServerServicePrefix) {
+		return nil
+	}
+
+	namespace, service, port, err := parseServiceReference(Server)
+	if err != nil {
+		return err
+	}
+
+	if err = discoverKubernetesCLI(); err != nil {
+		return err
+	}
+
+	// Fail clearly if the service doesn't exist, rather than with an opaque port-forward error
+	cmd := kubectlCommand("get", "service", service, "-n", namespace, "-o=name")
+	output, err := cmd.CombinedOutput()
+	recordCommand(cmd, output, err)
+	if err != nil {
+		return fmt.Errorf("could not find service %s in the %s namespace; %s",
+			service, namespace, strings.TrimSpace(string(output)))
+	}
+
+	localServer, err := startPortForward(namespace, service, port)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Forwarding %s to service %s/%s port %s.", localServer, namespace, service, port)
+
+	Server = localServer
+	return nil
+}
+
+// parseServiceReference splits a server of the form svc:<namespace>/<service>:<port>.
+func parseServiceReference(server string) (string, string, string, error) {
+
+	reference := strings.TrimPrefix(server, ServerServicePrefix)
+
+	invalidErr := fmt.Errorf("invalid service %s; expected %s<namespace>/<service>:<port>",
+		server, ServerServicePrefix)
+
+	slash := strings.Index(reference, "/")
+	colon := strings.LastIndex(reference, ":")
+	if slash <= 0 || colon <= slash+1 || colon == len(reference)-1 {
+		return "", "", "", invalidErr
+	}
+
+	return reference[:slash], reference[slash+1 : colon], reference[colon+1:], nil
+}
+
+// startPortForward forwards a random local port to a service port, returning the local address once
+// the forward is established.  The forward lasts until StopPortForward is called.
+func startPortForward(namespace, service, port string) (string, error) {
+
+	cmd := kubectlCommand("port-forward", "-n", namespace, "service/"+service, ":"+port)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err = cmd.Start(); err != nil {
+		return "", err
+	}
+	portForwardCmd = cmd
+
+	localPortChan := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, portForwardPrefix) {
+				localPortChan <- strings.Fields(strings.TrimPrefix(line, portForwardPrefix))[0]
+				break
+			}
+		}
+		close(localPortChan)
+
+		// Keep draining the output so the port-forward never blocks writing to it
+		io.Copy(ioutil.Discard, stdout)
+	}()
+
+	select {
+	case localPort, ok := <-localPortChan:
+		if !ok {
+			StopPortForward()
+			return "", errors.New("port-forward exited before forwarding any ports")
+		}
+		return "127.0.0.1:" + localPort, nil
+	case <-time.After(PodReadyTimeout):
+		StopPortForward()
+		return "", fmt.Errorf("port-forward was not ready after %3.2f seconds", PodReadyTimeout.Seconds())
+	}
+}
+
+// StopPortForward tears down any port-forward started by tridentctl.
+func StopPortForward() {
+
+	if portForwardCmd == nil || portForwardCmd.Process == nil {
+		return
+	}
+
+	portForwardCmd.Process.Kill()
+	portForwardCmd.Wait()
+	portForwardCmd = nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const redactedValue = "<REDACTED>"
+
+var (
+	Redact       bool
+	RedactFields []string
+)
+
+// DefaultRedactFields lists the names of fields known to contain credentials.
+var DefaultRedactFields = []string{
+	"password",
+	"secret",
+	"token",
+	"apiKey",
+	"accessKey",
+	"secretKey",
+	"privateKey",
+	"clientPrivateKey",
+	"clientSecret",
+	"chapInitiatorSecret",
+	"chapTargetInitiatorSecret",
+}
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&Redact, "redact", false,
+		"Mask sensitive fields (passwords, keys, tokens) in the output, e.g. before sharing it")
+	RootCmd.PersistentFlags().StringSliceVar(&RedactFields, "redact-fields", DefaultRedactFields,
+		"Names of the fields masked by --redact")
+}
+
+// isRedactedField returns whether a field name matches one of the fields to be redacted.
+func isRedactedField(name string) bool {
+	for _, field := range RedactFields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRedactedFieldPath returns whether a dotted field path, e.g. config.password, names a sensitive field.
+func isRedactedFieldPath(path string) bool {
+	segments := strings.Split(path, ".")
+	return isRedactedField(segments[len(segments)-1])
+}
+
+// redactSensitiveFields returns the JSON representation of a result with the values of all
+// sensitive fields, at any depth, replaced by a placeholder.
+func redactSensitiveFields(out interface{}) interface{} {
+
+	jsonBytes, err := json.Marshal(out)
+	if err != nil {
+		return out
+	}
+
+	var result interface{}
+	if err = json.Unmarshal(jsonBytes, &result); err != nil {
+		return out
+	}
+
+	redactValue(result)
+
+	return result
+}
+
+func redactValue(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, fieldValue := range v {
+			if isRedactedField(name) && fieldValue != nil {
+				v[name] = redactedValue
+			} else {
+				redactValue(fieldValue)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactValue(item)
+		}
+	}
+}
+
+// redactObjectChanges returns changes with the old and new values of sensitive fields replaced by a
+// placeholder.  A change names its field by path, so the field is matched by the last segment.
+func redactObjectChanges(changes []ObjectChanges) []ObjectChanges {
+
+	redacted := make([]ObjectChanges, 0, len(changes))
+	for _, objectChanges := range changes {
+
+		fieldChanges := make([]FieldChange, 0, len(objectChanges.Changes))
+		for _, change := range objectChanges.Changes {
+			if isRedactedFieldPath(change.Field) {
+				if change.Old != nil {
+					change.Old = redactedValue
+				}
+				if change.New != nil {
+					change.New = redactedValue
+				}
+			} else {
+				change.Old = redactSensitiveFields(change.Old)
+				change.New = redactSensitiveFields(change.New)
+			}
+			fieldChanges = append(fieldChanges, change)
+		}
+
+		redacted = append(redacted, ObjectChanges{Name: objectChanges.Name, Changes: fieldChanges})
+	}
+
+	return redacted
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteObjectChangesRedacted(t *testing.T) {
+
+	Redact = true
+	defer func() { Redact, OutputFormat = false, "" }()
+
+	changes := []ObjectChanges{{
+		Name: "ontapnas",
+		Changes: []FieldChange{
+			{Field: "config.password", Old: "oldSecret", New: "newSecret"},
+			{Field: "config.username", Old: "admin", New: "vsadmin"},
+			{Field: "config.chap", New: map[string]interface{}{"chapInitiatorSecret": "chapSecret"}},
+		},
+	}}
+
+	for _, format := range []string{FormatJSON, FormatYAML, ""} {
+		OutputFormat = format
+		output := captureStdout(t, func() { WriteObjectChanges(changes) })
+
+		assert.NotContains(t, output, "oldSecret", "old password written in format %q", format)
+		assert.NotContains(t, output, "newSecret", "new password written in format %q", format)
+		assert.NotContains(t, output, "chapSecret", "nested secret written in format %q", format)
+		assert.Contains(t, output, redactedValue)
+		assert.Contains(t, output, "vsadmin", "unrelated field redacted in format %q", format)
+	}
+
+	// The changes themselves are left as they were
+	assert.Equal(t, "oldSecret", changes[0].Changes[0].Old)
+}
+
+func TestWriteItemFieldRedacted(t *testing.T) {
+
+	Redact = true
+	defer func() { Redact, GetField = false, "" }()
+
+	items := []interface{}{map[string]interface{}{
+		"name":   "ontapnas",
+		"config": map[string]interface{}{"username": "vsadmin", "password": "secret"},
+	}}
+
+	GetField = "config.password"
+	output := captureStdout(t, func() { WriteItemField(items) })
+	assert.NotContains(t, output, "secret")
+	assert.Contains(t, output, redactedValue)
+
+	GetField = "config.username"
+	output = captureStdout(t, func() { WriteItemField(items) })
+	assert.Contains(t, output, "vsadmin", "unrelated field redacted")
+}
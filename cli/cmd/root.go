@@ -3,38 +3,73 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/cenkalti/backoff"
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/config"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	k8s "k8s.io/api/core/v1"
 )
 
 const (
-	FormatJSON = "json"
-	FormatName = "name"
-	FormatWide = "wide"
-	FormatYAML = "yaml"
+	FormatJSON        = "json"
+	FormatName        = "name"
+	FormatWide        = "wide"
+	FormatYAML        = "yaml"
+	FormatMarkdown    = "markdown"
+	FormatCount       = "count"
+	FormatCloudEvents = "cloudevents"
+	FormatAuto        = "auto"
+	FormatJSONPath    = "jsonpath"
 
 	ModeDirect  = "direct"
 	ModeTunnel  = "tunnel"
 	ModeInstall = "install"
 
+	// namespaceAll is the namespace that stands for every namespace, as with --all-namespaces
+	namespaceAll = "all"
+
 	CLIKubernetes = "kubectl"
 	CLIOpenshift  = "oc"
 
-	PodServer = "127.0.0.1:8000"
+	PodServer            = "127.0.0.1:8000"
+	PodServerHost        = "127.0.0.1"
+	DefaultPodServerPort = 8000
+
+	DefaultTunnelEntrypoint = "tridentctl"
+
+	PodReadyTimeout = 30 * time.Second
+
+	DefaultCLIProbeTimeout = 30 * time.Second
 
-	ExitCodeSuccess = 0
-	ExitCodeFailure = 1
+	podReasonEvicted = "Evicted"
+
+	// Exit codes, so scripts can distinguish why a command failed
+	ExitCodeSuccess        = 0
+	ExitCodeFailure        = 1
+	ExitCodePartialFailure = 2
+	ExitCodeNotFound       = 3   // no Trident pod was found
+	ExitCodeTimeout        = 124 // timed out, as reported by timeout(1)
+	ExitCodeInterrupted    = 130 // killed by SIGINT or SIGTERM, as a shell would report
+	exitCodeSignalBase     = 128 // added to the number of a signal that killed a command
 
 	TridentLabelKey   = "app"
 	TridentLabelValue = "trident.netapp.io"
@@ -53,6 +88,26 @@ const (
 	TridentInstallerLabel      = TridentInstallerLabelKey + "=" + TridentInstallerLabelValue
 )
 
+const (
+	// annotationSkipDiscovery marks commands that don't need a connection to Trident
+	annotationSkipDiscovery = "skipDiscovery"
+
+	// annotationNamespacedArgs marks commands whose arguments may be <namespace>/<name>
+	annotationNamespacedArgs = "namespacedArgs"
+
+	// annotationDiscoveryOptional marks commands that run even if discovery fails, finding the error
+	// in discoveryError
+	annotationDiscoveryOptional = "discoveryOptional"
+
+	// annotationNoExec marks commands that only read pod logs, so they work when exec is denied by RBAC
+	annotationNoExec = "noExec"
+)
+
+// outputFormats are the values of --output, in the order they are suggested
+var outputFormats = []string{
+	FormatAuto, FormatJSON, FormatYAML, FormatName, FormatWide, FormatMarkdown, FormatCount, FormatCloudEvents,
+}
+
 var (
 	OperatingMode       string
 	KubernetesCLI       string
@@ -60,133 +115,723 @@ var (
 	TridentPodNamespace string
 	ExitCode            int
 
-	Debug        bool
-	Server       string
-	OutputFormat string
-	CSI          bool
+	tridentContainers   []string
+	tridentPodCached    bool
+	tridentPodRefreshed bool
+	discoveryError      error
+
+	currentContext      string
+	currentContextErr   error
+	currentContextKnown bool
+
+	Debug         bool
+	Quiet         bool
+	Server        string
+	OutputFormat  string
+	CSI           bool
+	WaitReady     bool
+	DumpDiscovery bool
+	AllNamespaces bool
+
+	DiscoveryRetries uint
+	CLIProbeTimeout  time.Duration
+
+	UseTLS                bool
+	InsecureSkipTLSVerify bool
+
+	TunnelEntrypoint string
+	PodServerPort    = portValue(DefaultPodServerPort)
+	TridentContainer string
+	InPodNamespace   string
+	RESTRoot         string
+	BasePath         string
+	PodSelector      string
+	PodFieldSelector string
+	APIVersion       string
 )
 
 var RootCmd = &cobra.Command{
-	SilenceUsage: true,
-	Use:          "tridentctl",
-	Short:        "A CLI tool for NetApp Trident",
-	Long:         `A CLI tool for managing the NetApp Trident external storage provisioner for Kubernetes`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Use:           "tridentctl",
+	Short:         "A CLI tool for NetApp Trident",
+	Long:          `A CLI tool for managing the NetApp Trident external storage provisioner for Kubernetes`,
 }
 
 func init() {
 	RootCmd.PersistentFlags().BoolVarP(&Debug, "debug", "d", false, "Debug output")
-	RootCmd.PersistentFlags().StringVarP(&Server, "server", "s", "", "Address/port of Trident REST interface")
-	RootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", "", "Output format. One of json|yaml|name|wide|ps (default)")
-	RootCmd.PersistentFlags().StringVarP(&TridentPodNamespace, "namespace", "n", "", "Namespace of Trident deployment")
+	RootCmd.PersistentFlags().BoolVarP(&Quiet, "quiet", "q", false,
+		"Suppress non-essential output, such as warnings, leaving only results and errors")
+	RootCmd.PersistentFlags().StringVarP(&Server, "server", "s", "", "Address/port of Trident REST interface, or svc:<namespace>/<service>:<port>")
+	RootCmd.PersistentFlags().BoolVar(&UseTLS, "use-tls", false,
+		"Connect to the Trident REST interface with HTTPS when the server address has no scheme")
+	RootCmd.PersistentFlags().BoolVar(&InsecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"Don't verify the certificate of the Trident REST interface (insecure, for testing only)")
+	RootCmd.PersistentFlags().DurationVar(&api.HTTPTimeout, "request-timeout", api.DefaultHTTPTimeout,
+		"Timeout of each request to the Trident REST interface, or 0 for none")
+	RootCmd.PersistentFlags().BoolVar(&api.DryRun, "dry-run", false,
+		"Print each request to the Trident REST interface instead of sending it")
+	RootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", FormatAuto,
+		"Output format. One of "+strings.Join(outputFormats, "|")+"|"+FormatJSONPath+"=<expression>")
+	RootCmd.PersistentFlags().StringVarP(&TridentPodNamespace, "namespace", "n", "",
+		"Namespace of Trident deployment (env TRIDENT_NAMESPACE)")
+	RootCmd.PersistentFlags().BoolVarP(&AllNamespaces, "all-namespaces", "A", false,
+		"Search all namespaces for the Trident pod")
+	RootCmd.PersistentFlags().BoolVar(&WaitReady, "wait-ready", false,
+		"Wait for the Trident REST interface in the pod to respond before running the command")
+	RootCmd.PersistentFlags().UintVar(&DiscoveryRetries, "retries", 0,
+		"Number of times to retry a failed Kubernetes CLI command during discovery")
+	RootCmd.PersistentFlags().DurationVar(&CLIProbeTimeout, "cli-probe-timeout", DefaultCLIProbeTimeout,
+		"Time to wait for each Kubernetes CLI while detecting which to use, or 0 for no limit")
+	RootCmd.PersistentFlags().BoolVar(&DumpDiscovery, "dump-discovery", false,
+		"Write the raw output of the Trident pod discovery commands to stderr")
+	RootCmd.PersistentFlags().StringVar(&TunnelEntrypoint, "tunnel-entrypoint", DefaultTunnelEntrypoint,
+		"Command run in the Trident pod when tunneling (advanced; runs with the pod's privileges)")
+	RootCmd.PersistentFlags().MarkHidden("tunnel-entrypoint")
+	RootCmd.PersistentFlags().StringVar(&PodSelector, "pod-selector", TridentLabel,
+		"Label selector used to find the Trident pod")
+	RootCmd.PersistentFlags().StringVar(&PodFieldSelector, "pod-field-selector", "",
+		"Field selector further restricting the pods considered when finding the Trident pod")
+	RootCmd.PersistentFlags().Var(&PodServerPort, "pod-server-port",
+		"Port of the Trident REST interface within the Trident pod")
+	RootCmd.PersistentFlags().StringVar(&TridentContainer, "container", config.ContainerTrident,
+		"Name of the Trident container in the Trident pod")
+	RootCmd.PersistentFlags().StringVar(&InPodNamespace, "in-pod-namespace", "",
+		"Namespace passed to the tridentctl run inside the Trident pod (default none)")
+	RootCmd.PersistentFlags().StringVar(&RESTRoot, "rest-root", "",
+		"Path of the Trident REST interface replacing /trident/v1, or a whole URL, for testing against a mock server")
+	RootCmd.PersistentFlags().MarkHidden("rest-root")
+	RootCmd.PersistentFlags().StringVar(&BasePath, "base-path", "",
+		"Path of the Trident REST interface, such as behind a reverse proxy (default /trident/v1)")
+	RootCmd.PersistentFlags().StringVar(&APIVersion, "api-version", config.OrchestratorAPIVersion,
+		"Version of the Trident REST API to use")
 
 	RootCmd.PersistentFlags().BoolVar(&CSI, "csi", false, "Manage Trident as a CSI plugin (experimental)")
 	RootCmd.PersistentFlags().MarkHidden("csi")
 }
 
+func init() {
+	RootCmd.PersistentPreRunE = persistentPreRun
+}
+
+// persistentPreRun prepares to run any command, discovering how to reach Trident unless the command
+// doesn't need a connection.  Commands that override this, such as install, do their own discovery.
+func persistentPreRun(cmd *cobra.Command, args []string) error {
+
+	if hasAnnotation(cmd, annotationNamespacedArgs) {
+		if err := applyNamespacedArgs(cmd, args); err != nil {
+			return err
+		}
+	}
+
+	if Quiet && Debug {
+		return errors.New("--quiet and --debug may not be specified together")
+	}
+	if AllNamespaces && TridentPodNamespace != "" && TridentPodNamespace != namespaceAll {
+		return errors.New("--all-namespaces may not be combined with a namespace")
+	}
+
+	if err := validateKubeconfig(); err != nil {
+		return err
+	}
+
+	// A JSONPath expression is checked before discovery, so a mistake fails without any network call
+	if err := parseJSONPathFormat(); err != nil {
+		return err
+	}
+
+	// The output format may be set by the config files, which are loaded by discovery
+	defer resolveOutputFormat()
+
+	var err error
+	if skipDiscovery(cmd) {
+		err = loadClientConfig(cmd)
+	} else if err = discoverOperatingMode(cmd); err != nil && hasAnnotation(cmd, annotationDiscoveryOptional) {
+		// The command reports the failure itself, along with whatever it can do without Trident
+		discoveryError, err = err, nil
+	}
+
+	// A token given on the command line overrides any from the config files
+	if err == nil {
+		err = applyBearerToken()
+	}
+
+	// The output format is forwarded to tunneled commands, so catch any mistake before it gets there
+	if err == nil {
+		err = validateOutputFormat()
+	}
+
+	if err == nil && Explain {
+		explainCommand(cmd)
+	}
+
+	return err
+}
+
+// portValue is a flag value holding a TCP port number, which is validated when the flag is parsed.
+type portValue int
+
+func (p *portValue) String() string {
+	return strconv.Itoa(int(*p))
+}
+
+func (p *portValue) Set(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s is not a port number between 1 and 65535", value)
+	}
+	*p = portValue(port)
+	return nil
+}
+
+func (p *portValue) Type() string {
+	return "int"
+}
+
+// writeWarning writes a warning to stderr, unless --quiet was specified.
+func writeWarning(format string, args ...interface{}) {
+	if !Quiet {
+		fmt.Fprintf(os.Stderr, colorize(colorYellow, "Warning:")+" "+format+"\n", args...)
+	}
+}
+
+// validateOutputFormat checks that the output format is one of the known formats.
+func validateOutputFormat() error {
+
+	if OutputFormat == "" {
+		return nil
+	}
+	if err := parseJSONPathFormat(); err != nil {
+		return err
+	}
+	if OutputFormat == FormatJSONPath {
+		return nil
+	}
+	for _, format := range outputFormats {
+		if OutputFormat == format {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid output format %s; expected one of %s, or %s=<expression>",
+		OutputFormat, strings.Join(outputFormats, ", "), FormatJSONPath)
+}
+
+// skipDiscovery returns whether a command runs without a connection to Trident.  This is set with the
+// skipDiscovery annotation on the command or one of its parents, whose value is either "true" or the
+// name of a boolean flag that makes the command client-only when set (e.g. version --client).
+func skipDiscovery(cmd *cobra.Command) bool {
+
+	if cmd.Name() == "help" {
+		return true
+	}
+
+	for c := cmd; c != nil; c = c.Parent() {
+		value, ok := c.Annotations[annotationSkipDiscovery]
+		if !ok {
+			continue
+		}
+		if value == "true" {
+			return true
+		}
+		if flag := cmd.Flags().Lookup(value); flag != nil && flag.Value.String() == "true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasAnnotation returns whether a command or one of its parents has the specified annotation.
+func hasAnnotation(cmd *cobra.Command, annotation string) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, ok := c.Annotations[annotation]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func discoverOperatingMode(cmd *cobra.Command) error {
 
 	defer func() {
-		if !Debug {
+		if log.GetLevel() < log.DebugLevel {
 			return
 		}
 
 		switch OperatingMode {
 		case ModeDirect:
-			fmt.Printf("Operating mode = %s, Server = %s\n", OperatingMode, Server)
+			log.Debugf("Operating mode = %s, Server = %s", OperatingMode, Server)
 		case ModeTunnel:
-			fmt.Printf("Operating mode = %s, Trident pod = %s, Namespace = %s, CLI = %s\n",
-				OperatingMode, TridentPodName, TridentPodNamespace, KubernetesCLI)
+			kubeContext, _ := getCurrentContext()
+			log.Debugf("Operating mode = %s, Trident pod = %s, Container = %s, Namespace = %s, CLI = %s, "+
+				"Context = %s", OperatingMode, TridentPodName, TridentContainer, TridentPodNamespace,
+				KubernetesCLI, kubeContext)
 		}
 	}()
 
 	var err error
 
+	// Apply any defaults from the config files
+	if err = loadClientConfig(cmd); err != nil {
+		return err
+	}
+
+	// A server read from a Secret is used directly, since the pod already talks to Trident locally.
+	// It takes precedence over any server from the config files.
+	if ServerSecret != "" {
+		if cmd.Flags().Changed("server") {
+			return errors.New("--server-secret may not be combined with --server")
+		}
+		if err = loadServerSecret(); err != nil {
+			return err
+		}
+	}
+
 	envServer := os.Getenv("TRIDENT_SERVER")
 
 	if Server != "" {
 
 		// Server specified on command line takes precedence
 		OperatingMode = ModeDirect
-		return nil
+		if Server, err = normalizeServer(Server); err != nil {
+			return err
+		}
+		return resolveServiceServer()
 	} else if envServer != "" {
 
 		// Consider environment variable next
-		Server = envServer
+		OperatingMode = ModeDirect
+		if Server, err = normalizeServer(envServer); err != nil {
+			return err
+		}
+		return resolveServiceServer()
+	} else if strings.Contains(RESTRoot, "://") {
+
+		// A REST root with a scheme names the server itself
 		OperatingMode = ModeDirect
 		return nil
 	}
 
 	// To work with pods, we need to discover which CLI to invoke
-	err = discoverKubernetesCLI()
+	err = getCachedKubernetesCLI()
 	if err != nil {
 		return err
 	}
 
-	// Server not specified, so try tunneling to a pod
-	if TridentPodNamespace == "" {
-		if TridentPodNamespace, err = getCurrentNamespace(); err != nil {
+	// The tunnel entrypoint is run in the pod in place of tridentctl
+	if strings.TrimSpace(TunnelEntrypoint) == "" {
+		return errors.New("the tunnel entrypoint must not be empty")
+	}
+
+	if err = validateWorkload(); err != nil {
+		return err
+	}
+
+	// The namespace may be set in the environment, unless specified on the command line
+	if TridentPodNamespace == "" && !AllNamespaces {
+		TridentPodNamespace = os.Getenv("TRIDENT_NAMESPACE")
+	}
+
+	// As with kubectl, users may expect a namespace of "all" to search every namespace
+	if TridentPodNamespace == namespaceAll {
+		log.Debugf("Namespace %s specified, so searching all namespaces.", namespaceAll)
+		TridentPodNamespace, AllNamespaces = "", true
+	}
+
+	var tridentPod *k8s.Pod
+
+	// Helm users may identify Trident by its release, which also determines the namespace
+	if HelmRelease != "" {
+		if tridentPod, err = getHelmReleasePod(HelmRelease, TridentPodNamespace); err == nil {
+			TridentPodNamespace = tridentPod.Namespace
+		} else {
+			log.Debugf("%v; falling back to standard discovery.", err)
+		}
+	}
+
+	// Server not specified, so try tunneling to a pod, searching every namespace if requested
+	if TridentPodNamespace == "" && !AllNamespaces {
+		if TridentPodNamespace, err = getConfiguredNamespace(); err != nil {
+			return err
+		}
+	}
+	if TridentPodNamespace == "" && !AllNamespaces {
+		if TridentPodNamespace, err = getCachedNamespace(); err != nil {
 			return err
 		}
 	}
 
-	if CSI {
-		// Find the CSI Trident pod
-		if TridentPodName, err = getTridentPod(TridentPodNamespace, TridentCSILabel); err != nil {
+	if tridentPod == nil {
+		if tridentPod, err = getCachedTridentPod(TridentPodNamespace); err != nil {
+			// Commands that need Trident to be running exit with a distinct code; logs fail as before
+			if !hasAnnotation(cmd, annotationNoExec) {
+				err = &notFoundError{err}
+				SetExitCodeFromError(err)
+			}
 			return err
 		}
+		TridentPodNamespace = tridentPod.Namespace
+	}
+
+	setTridentPod(tridentPod)
+
+	OperatingMode = ModeTunnel
+	Server = net.JoinHostPort(PodServerHost, PodServerPort.String())
+
+	// Waiting for the REST interface requires exec, which commands that only read logs must not need
+	if WaitReady && !hasAnnotation(cmd, annotationNoExec) {
+		return waitForTridentREST()
+	}
+
+	return nil
+}
+
+// normalizeServer checks that a server address is a host, such as a DNS name like
+// trident-csi.trident.svc, with an optional port.  A typo thus fails clearly here rather than with a
+// cryptic connection error.  The default port of the Trident REST interface is appended to a bare host.
+// Service references and URLs with a scheme are left to be checked where they are used.
+func normalizeServer(server string) (string, error) {
+
+	if strings.HasPrefix(server, ServerServicePrefix) {
+		return server, nil
+	}
+	if strings.Contains(server, "://") {
+		if serverURL, err := url.Parse(server); err != nil || serverURL.Host == "" {
+			return "", fmt.Errorf("invalid server %s; expected a URL with a host", server)
+		}
+		return server, nil
+	}
+
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		// Not host:port, so the whole address must be a host, such as an IPv6 address without a port
+		host, port = strings.TrimSuffix(strings.TrimPrefix(server, "["), "]"), strconv.Itoa(DefaultPodServerPort)
+	}
+
+	if net.ParseIP(host) == nil && (len(host) > 253 || !dns1123DomainRegex.MatchString(strings.ToLower(host))) {
+		return "", fmt.Errorf("invalid server %s; expected a host name or IP address, with an optional port", server)
+	}
+	if portNumber, err := strconv.Atoi(port); err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", fmt.Errorf("invalid server %s; the port must be a number between 1 and 65535", server)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// discoverTridentPod returns the Trident pod in the specified namespace, falling back to the
+// CSI Trident pod unless CSI was requested explicitly.
+func discoverTridentPod(namespace string) (*k8s.Pod, error) {
+
+	if Workload != WorkloadAuto {
+		return getTridentWorkloadPod(namespace)
+	}
+
+	if PodSelector != TridentLabel {
+		// Find the pod by a customized label, such as from a Helm chart override
+		return getTridentPod(namespace, PodSelector)
+	}
+
+	if CSI {
+		// Find the CSI Trident pod
+		return getTridentPod(namespace, TridentCSILabel)
+	}
+
+	// Find the Trident pod
+	tridentPod, err := getTridentPod(namespace, TridentLabel)
+	if err != nil {
+
+		// Try falling back to CSI pod
+		return getTridentPod(namespace, TridentCSILabel)
+	}
+
+	return tridentPod, nil
+}
+
+// setTridentPod records the pod through which commands are tunneled.
+func setTridentPod(tridentPod *k8s.Pod) {
+	TridentPodName = tridentPod.Name
+	tridentContainers = nil
+	for _, container := range tridentPod.Spec.Containers {
+		tridentContainers = append(tridentContainers, container.Name)
+	}
+}
+
+// refreshTridentPod verifies that a cached Trident pod is still running just before a command is
+// tunneled through it, since during a rollout it may terminate while still cached.  If it is gone or
+// not running, discovery is repeated once to find its replacement.  A pod discovered by this
+// invocation is used as it is, and so is a pod that couldn't be checked, since the command run in it
+// reports any problem itself.
+func refreshTridentPod() error {
+
+	if TridentPodName == "" || !tridentPodCached || tridentPodRefreshed {
+		return nil
+	}
+
+	pod, err := getPod(TridentPodNamespace, TridentPodName)
+	if _, notFound := err.(*notFoundError); notFound {
+		log.Debugf("Trident pod %s no longer exists, repeating discovery.", TridentPodName)
+	} else if err != nil {
+		log.Debugf("Could not check Trident pod %s, using it anyway; %v", TridentPodName, err)
+		return nil
+	} else if pod.Status.Phase != k8s.PodRunning || pod.DeletionTimestamp != nil {
+		log.Debugf("Trident pod %s is no longer running, repeating discovery.", TridentPodName)
 	} else {
-		// Find the Trident pod
-		if TridentPodName, err = getTridentPod(TridentPodNamespace, TridentLabel); err != nil {
+		return nil
+	}
+
+	tridentPodRefreshed = true
+	tridentPod, err := discoverTridentPod(TridentPodNamespace)
+	if err != nil {
+		return err
+	}
+	setTridentPod(tridentPod)
 
-			// Try falling back to CSI pod
-			if TridentPodName, err = getTridentPod(TridentPodNamespace, TridentCSILabel); err != nil {
-				return err
+	return nil
+}
+
+// getPod returns the named pod, or a notFoundError if it doesn't exist.
+func getPod(namespace, name string) (*k8s.Pod, error) {
+
+	cmd := kubectlCommand("get", "pod", name, "-n", namespace, "-o=json")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	recordCommand(cmd, stderr.Bytes(), err)
+	if err != nil {
+		if strings.Contains(stderr.String(), "(NotFound)") {
+			return nil, &notFoundError{getDiscoveryError(err, stderr.Bytes())}
+		}
+		return nil, getDiscoveryError(err, stderr.Bytes())
+	}
+
+	var pod k8s.Pod
+	if err := json.Unmarshal(output, &pod); err != nil {
+		return nil, err
+	}
+
+	return &pod, nil
+}
+
+// waitForTridentREST probes the REST interface inside the Trident pod until it responds, so that
+// the first command after a pod restart doesn't fail with a refused connection.
+func waitForTridentREST() error {
+
+	checkRESTInterface := func() error {
+		output, err := TunnelCommandRaw([]string{"version", "-o", "json"})
+		if err == errInterrupted || commandTimedOut() {
+			return backoff.Permanent(err)
+		} else if err != nil {
+			if len(output) > 0 {
+				err = fmt.Errorf("%v; %s", err, strings.TrimSpace(string(output)))
 			}
+			return err
 		}
+		return nil
+	}
+	restNotify := func(err error, duration time.Duration) {
+		log.Debugf("Trident REST interface not yet up, waiting %v. %v", duration, err)
+	}
+	restBackoff := backoff.NewExponentialBackOff()
+	restBackoff.MaxElapsedTime = PodReadyTimeout
+
+	err := backoff.RetryNotify(checkRESTInterface, restBackoff, restNotify)
+	if err == errInterrupted || commandTimedOut() {
+		return err
+	} else if err != nil {
+		return fmt.Errorf("Trident REST interface was not available after %3.2f seconds; %v",
+			PodReadyTimeout.Seconds(), err)
 	}
 
-	OperatingMode = ModeTunnel
-	Server = PodServer
 	return nil
 }
 
 func discoverKubernetesCLI() error {
 
-	// Try the OpenShift CLI first
-	_, err := exec.Command(CLIOpenshift, "version").CombinedOutput()
-	if GetExitCodeFromError(err) == ExitCodeSuccess {
-		KubernetesCLI = CLIOpenshift
-		return nil
+	// Use the CLI specified, such as when both are installed but only kubectl is configured
+	if KubernetesCLIName != "" {
+		if KubernetesCLIName != CLIKubernetes && KubernetesCLIName != CLIOpenshift {
+			return fmt.Errorf("invalid Kubernetes CLI %s; expected %s or %s",
+				KubernetesCLIName, CLIKubernetes, CLIOpenshift)
+		}
+		if _, err := exec.LookPath(KubernetesCLIName); err != nil {
+			return fmt.Errorf("could not find the %s CLI; %v", KubernetesCLIName, err)
+		}
+		KubernetesCLI = KubernetesCLIName
+		return checkKubernetesCLI(KubernetesCLI)
+	}
+
+	// Probe both CLIs at once, so that a slow one doesn't delay the other, and kill any still
+	// running once the choice is made
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if CLIProbeTimeout > 0 {
+		ctx, cancel = context.WithTimeout(commandContext, CLIProbeTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(commandContext)
+	}
+	defer cancel()
+
+	ocProbe := probeKubernetesCLI(ctx, CLIOpenshift)
+	kubectlProbe := probeKubernetesCLI(ctx, CLIKubernetes)
+
+	// Prefer the OpenShift CLI, but only if the cluster is OpenShift
+	ocResult := <-ocProbe
+	if GetExitCodeFromError(ocResult.err) == ExitCodeSuccess {
+		if isOpenShiftServer(ocResult) {
+			KubernetesCLI = CLIOpenshift
+			return checkKubernetesCLIVersion(ocResult)
+		}
+		log.Debugf("The %s CLI is not connected to an OpenShift cluster, so trying %s.",
+			CLIOpenshift, CLIKubernetes)
+	} else if ctx.Err() == context.DeadlineExceeded {
+		log.Debugf("The %s CLI did not respond within %v, so trying %s.",
+			CLIOpenshift, CLIProbeTimeout, CLIKubernetes)
 	}
 
 	// Fall back to the K8S CLI
-	_, err = exec.Command(CLIKubernetes, "version").CombinedOutput()
-	if GetExitCodeFromError(err) == ExitCodeSuccess {
+	kubectlResult := <-kubectlProbe
+	if GetExitCodeFromError(kubectlResult.err) == ExitCodeSuccess {
 		KubernetesCLI = CLIKubernetes
-		return nil
+		return checkKubernetesCLIVersion(kubectlResult)
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("could not find the Kubernetes CLI; no CLI responded within %v", CLIProbeTimeout)
+	}
+
+	// Without kubectl, the OpenShift CLI works with any cluster
+	if isCLINotFound(kubectlResult.err) && GetExitCodeFromError(ocResult.err) == ExitCodeSuccess {
+		KubernetesCLI = CLIOpenshift
+		return checkKubernetesCLIVersion(ocResult)
+	}
+
+	// A CLI that runs but fails most likely couldn't reach the cluster, which its own error explains, so
+	// the CLI is only reported missing if neither is installed
+	for _, probe := range []cliProbe{kubectlResult, ocResult} {
+		if isCLINotFound(probe.err) {
+			continue
+		}
+		if err := checkKubernetesCLIVersion(probe); err != nil {
+			return err
+		}
+		return fmt.Errorf("the %s CLI failed; %v", probe.cli, getDiscoveryError(probe.err, probe.stderr))
+	}
+	return fmt.Errorf("could not find the Kubernetes CLI; neither %s nor %s is installed",
+		CLIKubernetes, CLIOpenshift)
+}
+
+// isCLINotFound returns whether a command failed because its executable isn't installed, rather than
+// because it ran and failed.
+func isCLINotFound(err error) bool {
+	execErr, ok := err.(*exec.Error)
+	return ok && execErr.Err == exec.ErrNotFound
+}
+
+// checkKubernetesCLI probes a Kubernetes CLI specified with --k8s-cli, and checks it just as discovery
+// would have.
+func checkKubernetesCLI(cli string) error {
+	return checkKubernetesCLIVersion(<-probeKubernetesCLI(commandContext, cli))
+}
+
+// cliProbe is the result of running 'version' with a Kubernetes CLI.
+type cliProbe struct {
+	cli    string
+	cmd    *exec.Cmd
+	output []byte
+	stderr []byte
+	err    error
+
+	// versions is nil if the CLI couldn't report its version as JSON
+	versions *kubernetesVersions
+}
+
+// probeKubernetesCLI runs 'version -o json' with a Kubernetes CLI in the background, returning a channel
+// that receives the result once the command completes or is killed when the context is done.  A CLI
+// too old to report its version as JSON is probed again with plain 'version'.
+func probeKubernetesCLI(ctx context.Context, cli string) <-chan cliProbe {
+
+	result := make(chan cliProbe, 1)
+	go func() {
+		probe := runCLIProbe(ctx, cli, "version", "-o", "json")
+
+		var versions kubernetesVersions
+		if err := json.Unmarshal(probe.output, &versions); err == nil && versions.ClientVersion != nil {
+			probe.versions = &versions
+		} else if _, ok := probe.err.(*exec.ExitError); ok && ctx.Err() == nil {
+			probe = runCLIProbe(ctx, cli, "version")
+		}
+
+		result <- probe
+	}()
+
+	return result
+}
+
+// runCLIProbe runs a command probing a Kubernetes CLI.  The command isn't recorded here, since probes
+// run concurrently, but by whoever uses the result.
+func runCLIProbe(ctx context.Context, cli string, args ...string) cliProbe {
+
+	cmd := exec.CommandContext(ctx, cli, kubectlArgs(args)...)
+	printCommand(cmd)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+
+	return cliProbe{cli: cli, cmd: cmd, output: output, stderr: stderr.Bytes(), err: err}
+}
+
+// isOpenShiftServer returns whether probing 'oc' found an OpenShift server.  Newer clients report an
+// OpenShift version only when the server is OpenShift.  Older clients, which can't report their version
+// as JSON, list an 'openshift' component for the server, or a 'Server Version' only when the server is
+// OpenShift.  Against plain Kubernetes, only the Kubernetes version is reported.
+func isOpenShiftServer(probe cliProbe) bool {
+
+	if probe.versions != nil {
+		return probe.versions.OpenShiftVersion != ""
 	}
 
-	return errors.New("could not find the Kubernetes CLI")
+	for _, line := range strings.Split(string(probe.output), "\n") {
+		line = strings.ToLower(strings.TrimSpace(line))
+		if strings.HasPrefix(line, "openshift ") || strings.HasPrefix(line, "server version:") {
+			return true
+		}
+	}
+	return false
+}
+
+// getCurrentContext returns the name of the current Kubernetes context, which is the one selected
+// with --context if specified.  The context is only looked up once per invocation.
+func getCurrentContext() (string, error) {
+
+	if KubernetesContext != "" {
+		return KubernetesContext, nil
+	}
+
+	if !currentContextKnown {
+		output, err := kubectlCommand("config", "current-context").Output()
+		if err != nil {
+			currentContextErr = fmt.Errorf("could not determine the current Kubernetes context; %v", err)
+		} else {
+			currentContext = strings.TrimSpace(string(output))
+		}
+		currentContextKnown = true
+	}
+
+	return currentContext, currentContextErr
 }
 
 // getCurrentNamespace returns the default namespace from service account info
 func getCurrentNamespace() (string, error) {
 
 	// Get current namespace from service account info
-	cmd := exec.Command(KubernetesCLI, "get", "serviceaccount", "default", "-o=json")
-	stdout, err := cmd.StdoutPipe()
+	output, err := runDiscoveryCommand("get", "serviceaccount", "default", "-o=json")
 	if err != nil {
 		return "", err
 	}
-	if err := cmd.Start(); err != nil {
-		return "", err
-	}
 
 	var serviceAccount k8s.ServiceAccount
-	if err := json.NewDecoder(stdout).Decode(&serviceAccount); err != nil {
-		return "", err
-	}
-	if err := cmd.Wait(); err != nil {
+	if err := json.Unmarshal(output, &serviceAccount); err != nil {
 		return "", err
 	}
 
@@ -198,112 +843,516 @@ func getCurrentNamespace() (string, error) {
 	return namespace, nil
 }
 
-// getTridentPod returns the name of the Trident pod in the specified namespace
-func getTridentPod(namespace, appLabel string) (string, error) {
+// getTridentPod returns the Trident pod in the specified namespace
+func getTridentPod(namespace, appLabel string) (*k8s.Pod, error) {
 
 	// Get 'trident' pod info
-	cmd := exec.Command(
-		KubernetesCLI,
-		"get", "pod",
-		"-n", namespace,
-		"-l", appLabel,
-		"-o=json",
-		"--field-selector=status.phase=Running",
-	)
-	stdout, err := cmd.StdoutPipe()
+	tridentPods, err := listRunningPods(namespace, appLabel)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if err := cmd.Start(); err != nil {
-		return "", err
+
+	// Pods in several namespaces are separate installations, so there's no telling which one is meant
+	if namespace == "" {
+		if podNames := getPodNamespacedNames(tridentPods.Items); len(podNames) > 1 {
+			return nil, fmt.Errorf("found Trident pods in several namespaces (%s). "+
+				"You may need to use the -n option to specify the correct namespace", strings.Join(podNames, ", "))
+		}
 	}
 
-	var tridentPod k8s.PodList
-	if err := json.NewDecoder(stdout).Decode(&tridentPod); err != nil {
-		return "", err
+	// During a rolling upgrade, several pods may match, so prefer one that is ready
+	if len(tridentPods.Items) > 1 {
+		if readyPods := filterReadyPods(tridentPods.Items); len(readyPods) > 0 {
+			log.Debugf("Found %d Trident pods, using ready pod %s.", len(tridentPods.Items), readyPods[0].Name)
+			return &readyPods[0], nil
+		}
+
+		podNames := make([]string, 0, len(tridentPods.Items))
+		for _, pod := range tridentPods.Items {
+			podNames = append(podNames, pod.Name)
+		}
+		return nil, fmt.Errorf("could not find a Trident pod in the %s namespace, since none of the %d "+
+			"pods matching the selector %s is ready (%s). You may need to use the --pod-selector option to refine the selector",
+			namespace, len(podNames), appLabel, strings.Join(podNames, ", "))
+	} else if len(tridentPods.Items) != 1 && namespace == "" {
+		return nil, errors.New("could not find a Trident pod in any namespace")
+	} else if len(tridentPods.Items) != 1 {
+		return nil, fmt.Errorf("could not find a Trident pod in the %s namespace. "+
+			"You may need to use the -n option to specify the correct namespace", namespace)
 	}
-	if err := cmd.Wait(); err != nil {
-		return "", err
+
+	return &tridentPods.Items[0], nil
+}
+
+// getPodNamespacedNames returns the namespace/name of each pod, or nil if the pods are all in the same
+// namespace.
+func getPodNamespacedNames(pods []k8s.Pod) []string {
+
+	namespaces := make(map[string]bool)
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+		names = append(names, pod.Namespace+"/"+pod.Name)
 	}
 
-	if len(tridentPod.Items) != 1 {
-		return "", fmt.Errorf("could not find a Trident pod in the %s namespace. "+
-			"You may need to use the -n option to specify the correct namespace", namespace)
+	if len(namespaces) < 2 {
+		return nil
 	}
+	return names
+}
 
-	// Get Trident pod name & namespace
-	name := tridentPod.Items[0].ObjectMeta.Name
+// listRunningPods returns the running pods matching a label selector in the specified namespace,
+// or in all namespaces if none is specified.
+func listRunningPods(namespace, selector string) (*k8s.PodList, error) {
+
+	args := []string{"get", "pod"}
+	if namespace == "" {
+		args = append(args, "--all-namespaces")
+	} else {
+		args = append(args, "-n", namespace)
+	}
+	// Only the last field selector given to the CLI applies, so any additional one is combined with ours
+	fieldSelector := "status.phase=Running"
+	if PodFieldSelector != "" {
+		fieldSelector += "," + PodFieldSelector
+	}
+	args = append(args, "-l", selector, "-o=json", "--field-selector="+fieldSelector)
+
+	output, err := runDiscoveryCommand(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods k8s.PodList
+	if err := json.Unmarshal(output, &pods); err != nil {
+		return nil, err
+	}
+
+	// Old pods may linger after eviction, so never rely on the field selector alone
+	pods.Items = filterLivePods(pods.Items)
+
+	return &pods, nil
+}
+
+// runDiscoveryCommand runs a Kubernetes CLI command for discovery, returning its output.  If the command
+// itself fails, such as while the API server is momentarily unavailable, it is retried up to --retries
+// times with exponential backoff.  Any output of a successful command is left for the caller to judge.
+func runDiscoveryCommand(args ...string) ([]byte, error) {
+
+	var output []byte
+
+	runCommand := func() error {
+
+		cmd := kubectlCommand(args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		if err = cmd.Start(); err != nil {
+			return backoff.Permanent(err)
+		}
+
+		if output, err = ioutil.ReadAll(stdout); err != nil {
+			return backoff.Permanent(err)
+		}
+		dumpDiscoveryOutput(cmd, output)
+		err = cmd.Wait()
+		recordCommand(cmd, stderr.Bytes(), err)
+		if err != nil {
+			// Don't repeat a command that the user interrupted
+			if _, interrupted := getTerminatingSignal(err); interrupted {
+				return backoff.Permanent(getDiscoveryError(err, stderr.Bytes()))
+			}
+			return getDiscoveryError(err, stderr.Bytes())
+		}
+		return nil
+	}
+	retryNotify := func(err error, duration time.Duration) {
+		log.Debugf("Discovery command failed, retrying in %v. %v", duration, err)
+	}
+	retryBackoff := backoff.WithMaxRetries(backoff.NewExponentialBackOff(), uint64(DiscoveryRetries))
+
+	if err := backoff.RetryNotify(runCommand, retryBackoff, retryNotify); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// filterLivePods returns only the pods that may be running, excluding any that have failed, completed,
+// or been evicted.
+func filterLivePods(pods []k8s.Pod) []k8s.Pod {
+
+	livePods := make([]k8s.Pod, 0, len(pods))
+	for _, pod := range pods {
+		switch {
+		case pod.Status.Phase == k8s.PodFailed, pod.Status.Phase == k8s.PodSucceeded:
+			continue
+		case pod.Status.Reason == podReasonEvicted:
+			continue
+		}
+		livePods = append(livePods, pod)
+	}
+	return livePods
+}
+
+// filterReadyPods returns only the running pods whose containers are all ready.
+func filterReadyPods(pods []k8s.Pod) []k8s.Pod {
+
+	readyPods := make([]k8s.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase != k8s.PodRunning || len(pod.Status.ContainerStatuses) == 0 {
+			continue
+		}
+		ready := true
+		for _, status := range pod.Status.ContainerStatuses {
+			if !status.Ready {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			readyPods = append(readyPods, pod)
+		}
+	}
+	return readyPods
+}
+
+// getDiscoveryError returns the error for a failed discovery command.  Failures to connect to the
+// cluster are reported distinctly, since they are easily confused with Trident not being found.  Any
+// other failure includes the stderr of the command.
+func getDiscoveryError(err error, stderr []byte) error {
+
+	// A command killed mid-stream leaves partial output, so report the interruption as the cause
+	if signal, ok := getTerminatingSignal(err); ok {
+		return fmt.Errorf("discovery command was interrupted (%v)", signal)
+	}
+
+	message := strings.TrimSpace(string(stderr))
+
+	for _, symptom := range []string{
+		"Unable to connect to the server",
+		"connection refused",
+		"no route to host",
+		"network is unreachable",
+		"no such host",
+		"i/o timeout",
+		"TLS handshake timeout",
+	} {
+		if strings.Contains(message, symptom) {
+			return fmt.Errorf("Kubernetes cluster is unreachable (check your kubeconfig/VPN); %s", message)
+		}
+	}
+
+	// The CLI explains other failures, such as of authentication or RBAC, only on stderr
+	if message != "" {
+		return fmt.Errorf("%v; %s", err, message)
+	}
+
+	return err
+}
+
+// getTerminatingSignal returns the signal that killed a command, if it was killed by one.
+func getTerminatingSignal(err error) (syscall.Signal, bool) {
+
+	exitError, ok := err.(*exec.ExitError)
+	if !ok {
+		return 0, false
+	}
+
+	waitStatus, ok := exitError.Sys().(syscall.WaitStatus)
+	if !ok || !waitStatus.Signaled() {
+		return 0, false
+	}
+
+	return waitStatus.Signal(), true
+}
 
-	return name, nil
+// dumpDiscoveryOutput writes the raw output of a discovery command to stderr if requested.
+func dumpDiscoveryOutput(cmd *exec.Cmd, output []byte) {
+	if DumpDiscovery {
+		fmt.Fprintf(diagnosticOutput, "Output of '%s':\n%s\n", strings.Join(cmd.Args, " "), string(output))
+	}
+}
+
+// checkTridentContainer verifies that the discovered Trident pod has the specified container, so
+// that a bad container name fails fast rather than with a cryptic error from the Kubernetes CLI.
+func checkTridentContainer(container string) error {
+
+	// Nothing to check if the pod wasn't discovered
+	if len(tridentContainers) == 0 {
+		return nil
+	}
+
+	for _, name := range tridentContainers {
+		if name == container {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("container %s not found in Trident pod %s; available containers: %s",
+		container, TridentPodName, strings.Join(tridentContainers, ", "))
 }
 
 func GetBaseURL() (string, error) {
 
-	url := fmt.Sprintf("http://%s%s", Server, config.BaseURL)
+	baseURL := config.BaseURL
+
+	// A reverse proxy may serve Trident under another path
+	if strings.Trim(BasePath, "/") != "" {
+		baseURL = "/" + strings.Trim(BasePath, "/")
+	}
+
+	// Substitute the version segment if a different API version was requested
+	apiVersion := strings.TrimPrefix(APIVersion, "v")
+	if apiVersion == "" {
+		return "", errors.New("the API version must not be empty")
+	} else if apiVersion != config.OrchestratorAPIVersion {
+		baseURL = strings.TrimSuffix(baseURL, "/v"+config.OrchestratorAPIVersion) + "/v" + apiVersion
+	}
 
-	if Debug {
-		fmt.Printf("Trident URL: %s\n", url)
+	// A REST root, such as of a mock server, replaces the path entirely, or the whole URL if it has a scheme
+	if RESTRoot != "" {
+		if strings.Contains(RESTRoot, "://") {
+			url := strings.TrimSuffix(RESTRoot, "/")
+			log.Debugf("Trident URL: %s", url)
+			return url, nil
+		}
+		baseURL = "/" + strings.Trim(RESTRoot, "/")
 	}
 
+	// The server may include a scheme, such as https:// or one from a Trident connection profile
+	scheme := "http"
+	if UseTLS {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, Server, baseURL)
+	if strings.Contains(Server, "://") {
+		url = strings.TrimSuffix(Server, "/") + baseURL
+	}
+
+	log.Debugf("Trident URL: %s", url)
+
 	return url, nil
 }
 
-func TunnelCommand(commandArgs []string) {
+// TunnelCommand runs a command in the Trident pod, streaming its output.  Output options are passed
+// along as far as the tridentctl in the pod supports them, so commands that write results should use
+// TunnelCommandJSON instead.
+func TunnelCommand(commandArgs []string, podFlags ...podFlag) {
 
-	// Build tunnel command to exec command in container
-	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", config.ContainerTrident, "--"}
+	output := OutputFormat
+	if !isPodOutputFormat(output) {
+		log.Debugf("Leaving out output format %s, which the tridentctl in the Trident pod may not support.",
+			output)
+		output = ""
+	}
 
-	// Build CLI command
-	cliCommand := []string{"tridentctl", "-s", Server}
-	if Debug {
-		cliCommand = append(cliCommand, "--debug")
+	err := runTunnelCommand(commandArgs, output, Debug, podFlags, os.Stdout)
+	SetExitCodeFromError(err)
+	if err == nil || err == errInterrupted {
+		return
 	}
-	if OutputFormat != "" {
-		cliCommand = append(cliCommand, []string{"--output", OutputFormat}...)
+
+	// A command that failed in the pod has already explained why
+	if _, ok := err.(*tunnelError); !ok {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 	}
-	cliCommand = append(cliCommand, commandArgs...)
+	writeFailureContext()
+}
 
-	// Combine tunnel and CLI commands
-	execCommand = append(execCommand, cliCommand...)
+// TunnelCommandJSON runs a command in the Trident pod with JSON output, decoding its result so that it
+// may be written here.  Output options, such as table truncation, grouping, and redaction, are thus
+// applied by this tridentctl, since the tridentctl in the pod is that of its Trident release and may
+// lack them.  With --dry-run, the requests the pod would send are written instead of a result, and
+// api.ErrDryRun is returned.
+func TunnelCommandJSON(commandArgs []string, result interface{}, podFlags ...podFlag) error {
 
-	if Debug {
-		fmt.Printf("Invoking tunneled command: %s %v\n", KubernetesCLI, strings.Join(execCommand, " "))
+	if api.DryRun {
+		if err := runTunnelCommand(commandArgs, "", false, podFlags, os.Stdout); err != nil {
+			return err
+		}
+		return api.ErrDryRun
 	}
 
+	// Debug messages of older tridentctl versions are written to stdout, where they'd corrupt the result
+	var stdout bytes.Buffer
+	if err := runTunnelCommand(commandArgs, FormatJSON, false, podFlags, &stdout); err != nil {
+		return err
+	}
+	log.Debugf("Tunneled command output: %s", stdout.String())
+
+	if err := json.Unmarshal(stdout.Bytes(), result); err != nil {
+		return fmt.Errorf("could not parse the result of the tunneled command; %v", err)
+	}
+	return nil
+}
+
+// isPodOutputFormat returns whether every version of the tridentctl in the Trident pod supports an
+// output format.
+func isPodOutputFormat(format string) bool {
+	switch format {
+	case "", FormatJSON, FormatYAML, FormatName, FormatWide:
+		return true
+	default:
+		return false
+	}
+}
+
+// runTunnelCommand runs a command in the Trident pod, writing its output to stdout and passing along
+// its stderr.  If the command fails in the pod, having explained why on stderr, a tunnelError is returned.
+func runTunnelCommand(commandArgs []string, output string, debug bool, podFlags []podFlag, stdout io.Writer) error {
+
+	if err := refreshTridentPod(); err != nil {
+		return err
+	}
+	if err := checkTridentContainer(TridentContainer); err != nil {
+		return err
+	}
+
+	cliCommand, err := getTunnelCLICommand(commandArgs, output, debug, podFlags)
+	if err != nil {
+		return err
+	}
+
+	// Build tunnel command to exec command in container
+	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", TridentContainer, "--"}
+	execCommand = append(execCommand, cliCommand...)
+
+	log.Debugf("Invoking tunneled command: %s %v", KubernetesCLI, strings.Join(execCommand, " "))
+
 	// Invoke tridentctl inside the Trident pod
-	out, err := exec.Command(KubernetesCLI, execCommand...).CombinedOutput()
+	ctx, cancel := getTunnelContext()
+	defer cancel()
+	cmd := kubectlCommandContext(ctx, execCommand...)
+
+	// Stream the output as it is written, keeping a copy of stderr for reporting a failure
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	err = cmd.Run()
+	if interruptContext.Err() != nil {
+		return errInterrupted
+	} else if commandTimedOut() {
+		return commandTimeoutError()
+	} else if ctx.Err() == context.DeadlineExceeded {
+		return &timeoutError{fmt.Errorf("tunneled command timed out after %v", api.HTTPTimeout)}
+	}
 
-	SetExitCodeFromError(err)
+	recordCommand(cmd, stderr.Bytes(), err)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s", string(out))
-	} else {
-		fmt.Print(string(out))
+		return &tunnelError{err}
 	}
+	return nil
+}
+
+// getTunnelCLICommand returns the tridentctl command run in the Trident pod.  Options that this
+// tridentctl applies to the result itself aren't passed along, and those the pod must apply are passed
+// along only if the tridentctl in the pod supports them.
+func getTunnelCLICommand(commandArgs []string, output string, debug bool, podFlags []podFlag) ([]string, error) {
+
+	cliCommand := []string{TunnelEntrypoint, "-s", Server}
+	if debug {
+		cliCommand = append(cliCommand, "--debug")
+	}
+	if output != "" {
+		cliCommand = append(cliCommand, []string{"--output", output}...)
+	}
+	if InPodNamespace != "" {
+		cliCommand = append(cliCommand, []string{"--namespace", InPodNamespace}...)
+	}
+	cliCommand = append(cliCommand, commandArgs...)
+
+	if Quiet {
+		podFlags = append(podFlags, optionalPodFlag("--quiet"))
+	}
+	if LogLevel != "" && !Debug {
+		podFlags = append(podFlags, optionalPodFlag("--log-level", LogLevel))
+	}
+	if api.HTTPTimeout != api.DefaultHTTPTimeout {
+		// A timeout the pod can't apply still bounds the tunneled command
+		podFlags = append(podFlags, optionalPodFlag("--request-timeout", api.HTTPTimeout.String()))
+	}
+	if APIVersion != config.OrchestratorAPIVersion {
+		podFlags = append(podFlags, requiredPodFlag("--api-version", APIVersion))
+	}
+	if VerboseErrors {
+		podFlags = append(podFlags, optionalPodFlag("--verbose-errors"))
+	}
+	if api.DryRun {
+		podFlags = append(podFlags, requiredPodFlag("--dry-run"))
+	}
+
+	return appendPodFlags(cliCommand, commandArgs, podFlags)
 }
 
 func TunnelCommandRaw(commandArgs []string) ([]byte, error) {
 
+	if err := refreshTridentPod(); err != nil {
+		SetExitCodeFromError(err)
+		return nil, err
+	}
+	if err := checkTridentContainer(TridentContainer); err != nil {
+		SetExitCodeFromError(err)
+		return nil, err
+	}
+
 	// Build tunnel command to exec command in container
-	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", config.ContainerTrident, "--"}
+	execCommand := []string{"exec", TridentPodName, "-n", TridentPodNamespace, "-c", TridentContainer, "--"}
 
 	// Build CLI command
-	cliCommand := []string{"tridentctl", "-s", Server}
+	cliCommand := []string{TunnelEntrypoint, "-s", Server}
+	if InPodNamespace != "" {
+		cliCommand = append(cliCommand, []string{"--namespace", InPodNamespace}...)
+	}
 	cliCommand = append(cliCommand, commandArgs...)
 
 	// Combine tunnel and CLI commands
 	execCommand = append(execCommand, cliCommand...)
 
-	if Debug {
-		fmt.Printf("Invoking tunneled command: %s %v\n", KubernetesCLI, strings.Join(execCommand, " "))
-	}
+	log.Debugf("Invoking tunneled command: %s %v", KubernetesCLI, strings.Join(execCommand, " "))
 
 	// Invoke tridentctl inside the Trident pod
-	output, err := exec.Command(KubernetesCLI, execCommand...).CombinedOutput()
+	ctx, cancel := getTunnelContext()
+	defer cancel()
+	cmd := kubectlCommandContext(ctx, execCommand...)
+	output, err := cmd.CombinedOutput()
+	if interruptContext.Err() != nil {
+		err = errInterrupted
+	} else if commandTimedOut() {
+		err = commandTimeoutError()
+	} else if ctx.Err() == context.DeadlineExceeded {
+		err = &timeoutError{fmt.Errorf("tunneled command timed out after %v", api.HTTPTimeout)}
+	}
 
 	SetExitCodeFromError(err)
+	recordCommand(cmd, nil, err)
 	return output, err
 }
 
+// getTunnelContext returns the context of a tunneled command, which is cancelled if tridentctl is
+// interrupted or times out.  If a request timeout was specified, it bounds the whole command, since
+// the REST requests are made by the tridentctl in the pod.  Otherwise tunneled commands aren't
+// bounded, so that long-running commands such as wait aren't interrupted.
+func getTunnelContext() (context.Context, context.CancelFunc) {
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if RootCmd.PersistentFlags().Changed("request-timeout") && api.HTTPTimeout > 0 {
+		ctx, cancel = context.WithTimeout(commandContext, api.HTTPTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(commandContext)
+	}
+
+	atomic.AddInt32(&tunnelsRunning, 1)
+	return ctx, func() {
+		atomic.AddInt32(&tunnelsRunning, -1)
+		cancel()
+	}
+}
+
 func GetErrorFromHTTPResponse(response *http.Response, responseBody []byte) error {
 
 	var errorResponse api.ErrorResponse
@@ -322,12 +1371,33 @@ func GetExitCodeFromError(err error) int {
 		return ExitCodeSuccess
 	} else {
 
+		// A command that failed in the Trident pod exits as it did there
+		if tunnelErr, ok := err.(*tunnelError); ok {
+			err = tunnelErr.err
+		}
+
 		// Default to 1 in case we can't determine a process exit code
 		code := ExitCodeFailure
 
-		if exitError, ok := err.(*exec.ExitError); ok {
+		if commandTimedOut() {
+			// Whatever failed was most likely stopped by the timeout, perhaps by killing a process
+			code = ExitCodeTimeout
+		} else if signal, ok := getTerminatingSignal(err); ok {
+			// A command killed by a signal has no exit status, so report the signal as a shell would
+			code = exitCodeSignalBase + int(signal)
+		} else if exitError, ok := err.(*exec.ExitError); ok {
 			ws := exitError.Sys().(syscall.WaitStatus)
 			code = ws.ExitStatus()
+		} else if _, ok := err.(*timeoutError); ok || err == context.DeadlineExceeded {
+			code = ExitCodeTimeout
+		} else if multiError, ok := err.(*MultiError); ok && multiError.Partial() {
+			code = ExitCodePartialFailure
+		} else if _, ok := err.(*notFoundError); ok {
+			code = ExitCodeNotFound
+		} else if err == errInterrupted {
+			code = ExitCodeInterrupted
+		} else if isDryRun(err) {
+			code = ExitCodeSuccess
 		}
 
 		return code
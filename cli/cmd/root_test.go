@@ -0,0 +1,204 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8s "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetDiscoveryErrorKilled(t *testing.T) {
+
+	// Simulate a discovery command killed mid-stream, e.g. by a timeout
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Could not start test command; %v", err)
+	}
+	cmd.Process.Signal(syscall.SIGKILL)
+	waitErr := cmd.Wait()
+
+	err := getDiscoveryError(waitErr, []byte("partial output"))
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "discovery command was interrupted"),
+		"unexpected error: %v", err)
+}
+
+func TestGetDiscoveryErrorExited(t *testing.T) {
+
+	cmd := exec.Command("false")
+	waitErr := cmd.Run()
+	if waitErr == nil {
+		t.Skip("Test command unexpectedly succeeded")
+	}
+
+	err := getDiscoveryError(waitErr, nil)
+	assert.Equal(t, waitErr, err, "a command that exited should not be reported as interrupted")
+
+	err = getDiscoveryError(waitErr, []byte("Unable to connect to the server: dial tcp: i/o timeout"))
+	assert.Contains(t, err.Error(), "Kubernetes cluster is unreachable")
+
+	err = getDiscoveryError(waitErr, []byte("error: You must be logged in to the server (Unauthorized)\n"))
+	assert.Equal(t, waitErr.Error()+"; error: You must be logged in to the server (Unauthorized)", err.Error())
+}
+
+func TestIsCLINotFound(t *testing.T) {
+
+	err := exec.Command("tridentctl-test-missing-cli", "version").Run()
+	assert.True(t, isCLINotFound(err), "expected a missing executable to be reported as not found")
+
+	err = exec.Command("false").Run()
+	if err == nil {
+		t.Skip("Test command unexpectedly succeeded")
+	}
+	assert.False(t, isCLINotFound(err), "a command that ran and failed should not be reported as not found")
+	assert.False(t, isCLINotFound(nil))
+}
+
+func TestFilterLivePods(t *testing.T) {
+
+	// An evicted pod lingering alongside its replacement, as seen on nodes under memory pressure
+	podListJSON := `{
+		"kind": "List",
+		"items": [
+			{"metadata": {"name": "trident-old"}, "status": {"phase": "Failed", "reason": "Evicted"}},
+			{"metadata": {"name": "trident-evicted"}, "status": {"phase": "Running", "reason": "Evicted"}},
+			{"metadata": {"name": "trident-done"}, "status": {"phase": "Succeeded"}},
+			{"metadata": {"name": "trident-new"}, "status": {"phase": "Running"}}
+		]
+	}`
+
+	var pods k8s.PodList
+	if err := json.Unmarshal([]byte(podListJSON), &pods); err != nil {
+		t.Fatalf("Could not parse pod fixture; %v", err)
+	}
+
+	livePods := filterLivePods(pods.Items)
+	if assert.Len(t, livePods, 1) {
+		assert.Equal(t, "trident-new", livePods[0].Name)
+	}
+}
+
+func TestFilterLivePodsNone(t *testing.T) {
+
+	pods := []k8s.Pod{
+		{Status: k8s.PodStatus{Phase: k8s.PodFailed, Reason: "Evicted"}},
+		{Status: k8s.PodStatus{Phase: k8s.PodSucceeded}},
+	}
+
+	assert.Empty(t, filterLivePods(pods))
+}
+
+func TestFilterReadyPods(t *testing.T) {
+
+	// The old and new pods of a rolling upgrade, only one of which is ready
+	pods := []k8s.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "trident-old"},
+			Status: k8s.PodStatus{Phase: k8s.PodRunning, ContainerStatuses: []k8s.ContainerStatus{
+				{Name: "trident-main", Ready: true}, {Name: "etcd", Ready: false},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "trident-new"},
+			Status: k8s.PodStatus{Phase: k8s.PodRunning, ContainerStatuses: []k8s.ContainerStatus{
+				{Name: "trident-main", Ready: true}, {Name: "etcd", Ready: true},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "trident-pending"},
+			Status:     k8s.PodStatus{Phase: k8s.PodPending},
+		},
+	}
+
+	readyPods := filterReadyPods(pods)
+	if assert.Len(t, readyPods, 1) {
+		assert.Equal(t, "trident-new", readyPods[0].Name)
+	}
+}
+
+func TestNormalizeServer(t *testing.T) {
+
+	tests := []struct {
+		server   string
+		expected string
+	}{
+		{"10.0.0.1:8000", "10.0.0.1:8000"},
+		{"trident-csi.trident.svc:8001", "trident-csi.trident.svc:8001"},
+		{"trident-csi.trident.svc", "trident-csi.trident.svc:8000"},
+		{"Trident.Example.com", "Trident.Example.com:8000"},
+		{"::1", "[::1]:8000"},
+		{"[::1]:8443", "[::1]:8443"},
+		{"https://trident.example.com:8443", "https://trident.example.com:8443"},
+		{"svc:trident/trident-csi:8000", "svc:trident/trident-csi:8000"},
+	}
+	for _, test := range tests {
+		server, err := normalizeServer(test.server)
+		assert.NoError(t, err, "unexpected error for %s", test.server)
+		assert.Equal(t, test.expected, server)
+	}
+}
+
+func TestNormalizeServerInvalid(t *testing.T) {
+
+	for _, server := range []string{
+		"trident-csi.trident.svc:http",
+		"trident-csi.trident.svc:70000",
+		"trident-csi.trident.svc:",
+		"trident_csi.trident.svc:8000",
+		"trident-csi..svc",
+		"https://",
+	} {
+		_, err := normalizeServer(server)
+		assert.Error(t, err, "expected an error for %s", server)
+	}
+}
+
+func TestGetExitCodeFromErrorExited(t *testing.T) {
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	if _, ok := err.(*exec.ExitError); !ok {
+		t.Skipf("Test command did not fail as expected; %v", err)
+	}
+
+	assert.Equal(t, 3, GetExitCodeFromError(err))
+}
+
+func TestGetExitCodeFromErrorSignaled(t *testing.T) {
+
+	cmd := exec.Command("sleep", "10")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Could not start test command; %v", err)
+	}
+	cmd.Process.Signal(syscall.SIGTERM)
+	err := cmd.Wait()
+
+	assert.Equal(t, 128+int(syscall.SIGTERM), GetExitCodeFromError(err))
+}
+
+func TestGetExitCodeFromErrorTimeout(t *testing.T) {
+
+	assert.Equal(t, ExitCodeTimeout, GetExitCodeFromError(context.DeadlineExceeded))
+	assert.Equal(t, ExitCodeTimeout, GetExitCodeFromError(&timeoutError{errors.New("timed out")}))
+	assert.Equal(t, ExitCodeFailure, GetExitCodeFromError(errors.New("failed")))
+	assert.Equal(t, ExitCodeSuccess, GetExitCodeFromError(nil))
+}
+
+func TestMultiErrorAction(t *testing.T) {
+
+	single := NewMultiError(1)
+	single.AppendAction("b1", "delete backend", errors.New("not found"))
+	assert.Equal(t, "could not delete backend b1: not found", single.ErrorOrNil().Error())
+
+	several := NewMultiError(2)
+	several.AppendAction("b1", "delete backend", errors.New("not found"))
+	assert.Equal(t, "1 of 2 operations failed:\n  b1: not found", several.ErrorOrNil().Error())
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	k8s "k8s.io/api/core/v1"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+const (
+	serverSecretServerKey = "server"
+	serverSecretTokenKey  = "token"
+)
+
+var ServerSecret string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&ServerSecret, "server-secret", "",
+		"Secret (<namespace>/<name>) holding the address of the Trident REST interface and an optional bearer token")
+}
+
+// loadServerSecret reads the server, and any bearer token, from the Secret named by --server-secret, so
+// that credentials needn't appear on the command line.  The Secret's values are never logged.
+func loadServerSecret() error {
+
+	namespace, name, err := parseServerSecret(ServerSecret)
+	if err != nil {
+		return err
+	}
+
+	if err = discoverKubernetesCLI(); err != nil {
+		return err
+	}
+
+	cmd := kubectlCommand("get", "secret", name, "-n", namespace, "-o=json")
+	output, err := cmd.Output()
+	var stderr []byte
+	if exitError, ok := err.(*exec.ExitError); ok {
+		stderr = exitError.Stderr
+	}
+	recordCommand(cmd, stderr, err)
+	if err != nil {
+		return fmt.Errorf("could not read secret %s in the %s namespace; %v", name, namespace,
+			getDiscoveryError(err, stderr))
+	}
+
+	var secret k8s.Secret
+	if err = json.Unmarshal(output, &secret); err != nil {
+		return fmt.Errorf("could not parse secret %s in the %s namespace; %v", name, namespace, err)
+	}
+
+	server := strings.TrimSpace(string(secret.Data[serverSecretServerKey]))
+	if server == "" {
+		return fmt.Errorf("secret %s in the %s namespace has no %s key", name, namespace, serverSecretServerKey)
+	}
+	Server = server
+
+	if token := strings.TrimSpace(string(secret.Data[serverSecretTokenKey])); token != "" {
+		api.BearerToken = token
+	}
+
+	return nil
+}
+
+// parseServerSecret splits a secret reference of the form <namespace>/<name>.
+func parseServerSecret(reference string) (string, string, error) {
+
+	parts := strings.Split(reference, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid server secret %s; expected <namespace>/<name>", reference)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	minColumnWidth = 10
+	ellipsis       = "..."
+)
+
+var (
+	MaxColumnWidth int
+	NoTruncate     bool
+	TerminalWidth  int
+)
+
+func init() {
+	RootCmd.PersistentFlags().IntVar(&MaxColumnWidth, "max-column-width", 0,
+		"Truncate table cells longer than this width (default fits the table to the terminal)")
+	RootCmd.PersistentFlags().BoolVar(&NoTruncate, "no-truncate", false, "Never truncate table cells")
+	RootCmd.PersistentFlags().IntVar(&TerminalWidth, "terminal-width", 0, "Width of the terminal")
+	RootCmd.PersistentFlags().MarkHidden("terminal-width")
+}
+
+// tableWriter wraps a tablewriter.Table, truncating long cell values with an ellipsis so that the
+// table fits the terminal or the requested maximum column width.
+type tableWriter struct {
+	*tablewriter.Table
+	header []string
+	rows   [][]string
+}
+
+func newTableWriter() *tableWriter {
+	return &tableWriter{Table: tablewriter.NewWriter(os.Stdout)}
+}
+
+func (t *tableWriter) SetHeader(header []string) {
+	if AnnotateSource {
+		header = append(append([]string{}, header...), "Context")
+	}
+	t.header = header
+	t.Table.SetHeader(header)
+}
+
+func (t *tableWriter) Append(row []string) {
+	if AnnotateSource {
+		context, _ := getSource()
+		row = append(append([]string{}, row...), context)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *tableWriter) Render() {
+
+	if OutputFormat == FormatMarkdown {
+		t.renderMarkdown()
+		return
+	}
+
+	widths := t.columnWidths()
+	if widths != nil {
+		t.Table.SetAutoWrapText(false)
+	}
+
+	for _, row := range t.rows {
+		if Redact {
+			row = t.redactRow(row)
+		}
+		if widths != nil {
+			truncatedRow := make([]string, len(row))
+			for i, value := range row {
+				truncatedRow[i] = truncateValue(value, widths[i])
+			}
+			row = truncatedRow
+		}
+		t.Table.Append(row)
+	}
+
+	t.Table.Render()
+}
+
+// writeTable writes rows under a header with the shared table writer, so that commands without a
+// writer of their own align, truncate, and redact cells like every other table.  Wide output is
+// meant to show everything, so its cells are never truncated.
+func writeTable(header []string, rows [][]string, wide bool) {
+
+	table := newTableWriter()
+	table.SetHeader(header)
+	for _, row := range rows {
+		table.Append(row)
+	}
+
+	if wide {
+		noTruncate := NoTruncate
+		NoTruncate = true
+		defer func() { NoTruncate = noTruncate }()
+	}
+
+	table.Render()
+}
+
+// renderMarkdown writes the table as a GitHub-flavored Markdown table.  Markdown output is meant
+// to be pasted elsewhere, so cells are never truncated.
+func (t *tableWriter) renderMarkdown() {
+
+	rows := make([][]string, 0, len(t.rows))
+	for _, row := range t.rows {
+		if Redact {
+			row = t.redactRow(row)
+		}
+		escapedRow := make([]string, len(t.header))
+		for i := range escapedRow {
+			if i < len(row) {
+				escapedRow[i] = escapeMarkdownCell(row[i])
+			}
+		}
+		rows = append(rows, escapedRow)
+	}
+
+	header := make([]string, len(t.header))
+	widths := make([]int, len(t.header))
+	for i, value := range t.header {
+		header[i] = escapeMarkdownCell(value)
+		widths[i] = utf8.RuneCountInString(header[i])
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+		for _, row := range rows {
+			if utf8.RuneCountInString(row[i]) > widths[i] {
+				widths[i] = utf8.RuneCountInString(row[i])
+			}
+		}
+	}
+
+	separator := make([]string, len(t.header))
+	for i := range separator {
+		separator[i] = strings.Repeat("-", widths[i])
+	}
+
+	writeMarkdownRow(header, widths)
+	writeMarkdownRow(separator, widths)
+	for _, row := range rows {
+		writeMarkdownRow(row, widths)
+	}
+}
+
+func writeMarkdownRow(row []string, widths []int) {
+
+	cells := make([]string, len(row))
+	for i, value := range row {
+		cells[i] = value + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(value))
+	}
+	fmt.Printf("| %s |\n", strings.Join(cells, " | "))
+}
+
+// escapeMarkdownCell escapes the characters that would otherwise break a Markdown table cell.
+func escapeMarkdownCell(value string) string {
+	value = strings.Replace(value, "|", "\\|", -1)
+	return strings.Replace(value, "\n", " ", -1)
+}
+
+// redactRow masks the cells of any columns whose header names a sensitive field.
+func (t *tableWriter) redactRow(row []string) []string {
+
+	redactedRow := make([]string, len(row))
+	for i, value := range row {
+		if i < len(t.header) && isRedactedField(strings.Replace(t.header[i], " ", "", -1)) && value != "" {
+			value = redactedValue
+		}
+		redactedRow[i] = value
+	}
+	return redactedRow
+}
+
+// columnWidths returns the maximum width of each column, or nil if no truncation is needed.
+func (t *tableWriter) columnWidths() []int {
+
+	if NoTruncate || len(t.header) == 0 {
+		return nil
+	}
+
+	widths := make([]int, len(t.header))
+
+	if MaxColumnWidth > 0 {
+		for i := range widths {
+			widths[i] = MaxColumnWidth
+		}
+		return widths
+	}
+
+	terminalWidth := getTerminalWidth()
+	if terminalWidth <= 0 {
+		return nil
+	}
+
+	// Start with the natural width of each column, plus the borders and padding
+	floors := make([]int, len(t.header))
+	tableWidth := 3*len(t.header) + 1
+	for i, header := range t.header {
+		widths[i] = utf8.RuneCountInString(header)
+		for _, row := range t.rows {
+			if i < len(row) && utf8.RuneCountInString(row[i]) > widths[i] {
+				widths[i] = utf8.RuneCountInString(row[i])
+			}
+		}
+		floors[i] = utf8.RuneCountInString(header)
+		if floors[i] < minColumnWidth {
+			floors[i] = minColumnWidth
+		}
+		tableWidth += widths[i]
+	}
+
+	// Narrow the widest columns until the table fits
+	for tableWidth > terminalWidth {
+		widest := -1
+		for i := range widths {
+			if widths[i] > floors[i] && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+		tableWidth--
+	}
+
+	return widths
+}
+
+// getTerminalWidth returns the width of the terminal attached to stdout, or 0 if there isn't one.
+func getTerminalWidth() int {
+
+	if TerminalWidth > 0 {
+		return TerminalWidth
+	}
+
+	if !stdoutIsTerminal() {
+		return 0
+	}
+
+	width, _, err := terminal.GetSize(int(resultStdout().Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// truncateValue shortens a value to the specified width, ending it with an ellipsis.
+func truncateValue(value string, width int) string {
+
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	if width <= len(ellipsis) {
+		return string(runes[:width])
+	}
+	return string(runes[:width-len(ellipsis)]) + ellipsis
+}
@@ -0,0 +1,127 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// captureStdout returns whatever the function writes to stdout.
+func captureStdout(t *testing.T, f func()) string {
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Could not create pipe; %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	f()
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Could not read output; %v", err)
+	}
+	return string(output)
+}
+
+type testOutput struct {
+	Items [][]string `json:"items"`
+}
+
+func (o testOutput) tableHeader(wide bool) []string {
+	return []string{"Name", "State"}
+}
+
+func (o testOutput) tableRows(wide bool) [][]string {
+	return o.Items
+}
+
+func (o testOutput) names() []string {
+	names := make([]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		names = append(names, item[0])
+	}
+	return names
+}
+
+func TestWriteTableAlignment(t *testing.T) {
+
+	OutputFormat = ""
+	output := captureStdout(t, func() {
+		writeTable([]string{"Name", "State"}, [][]string{
+			{"a", "online"},
+			{"a-much-longer-name", "offline"},
+		}, false)
+	})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	assert.True(t, len(lines) > 3, "unexpected table: %s", output)
+
+	// Every line is as wide as the first, with its column separators in the same places
+	separators := func(line string) []int {
+		var positions []int
+		for i, r := range []rune(line) {
+			if r == '|' || r == '+' {
+				positions = append(positions, i)
+			}
+		}
+		return positions
+	}
+	for _, line := range lines {
+		assert.Equal(t, utf8.RuneCountInString(lines[0]), utf8.RuneCountInString(line), "misaligned line %q", line)
+		assert.Equal(t, separators(lines[0]), separators(line), "misaligned line %q", line)
+	}
+	assert.Contains(t, output, "a-much-longer-name")
+}
+
+func TestWriteTableWideNoTruncation(t *testing.T) {
+
+	OutputFormat = ""
+	MaxColumnWidth = 10
+	defer func() { MaxColumnWidth = 0 }()
+
+	longName := strings.Repeat("x", 30)
+	output := captureStdout(t, func() {
+		writeTable([]string{"Name"}, [][]string{{longName}}, true)
+	})
+	assert.Contains(t, output, longName)
+
+	output = captureStdout(t, func() {
+		writeTable([]string{"Name"}, [][]string{{longName}}, false)
+	})
+	assert.NotContains(t, output, longName)
+	assert.False(t, NoTruncate, "NoTruncate was not restored")
+}
+
+func TestWriteOutputName(t *testing.T) {
+
+	OutputFormat = FormatName
+	defer func() { OutputFormat = "" }()
+
+	output := captureStdout(t, func() {
+		writeOutput(testOutput{Items: [][]string{{"first", "online"}, {"second", "offline"}}})
+	})
+	assert.Equal(t, "first\nsecond\n", output)
+}
+
+func TestWriteOutputNameWithoutNames(t *testing.T) {
+
+	OutputFormat = FormatName
+	defer func() { OutputFormat = "" }()
+
+	// Results without names fall back to a table
+	output := captureStdout(t, func() {
+		writeOutput(ConnectionInfo{OperatingMode: ModeDirect, Server: "10.0.0.1:8000"})
+	})
+	assert.Contains(t, output, "10.0.0.1:8000")
+	assert.Contains(t, output, "|")
+}
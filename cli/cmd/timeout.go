@@ -0,0 +1,66 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+// commandTimeoutGrace is how long a timed out command has to stop on its own before tridentctl exits
+const commandTimeoutGrace = 5 * time.Second
+
+var (
+	CommandTimeout time.Duration
+
+	// commandContext bounds everything tridentctl does, including discovery, and is done when the
+	// command times out or tridentctl is interrupted
+	commandContext, cancelCommand = context.WithCancel(interruptContext)
+)
+
+func init() {
+	RootCmd.PersistentFlags().DurationVar(&CommandTimeout, "timeout", 0,
+		"How long the whole command, including discovery, may run before it is stopped (0 for no limit)")
+	cobra.OnInitialize(initCommandTimeout)
+}
+
+// initCommandTimeout applies any command timeout to the command context.  Commands sent to the cluster
+// and requests sent to Trident are stopped at the deadline, so the command fails on its own; anything
+// that doesn't notice the deadline is given a short grace period before tridentctl exits regardless.
+func initCommandTimeout() {
+
+	if CommandTimeout <= 0 {
+		return
+	}
+
+	cancelCommand()
+	commandContext, cancelCommand = context.WithTimeout(interruptContext, CommandTimeout)
+	api.RequestContext = commandContext
+
+	go func() {
+		<-commandContext.Done()
+		if !commandTimedOut() {
+			return
+		}
+		time.Sleep(commandTimeoutGrace)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", commandTimeoutError())
+		Exit(ExitCodeTimeout)
+	}()
+}
+
+// commandTimedOut returns true if the command ran past --timeout.
+func commandTimedOut() bool {
+	return commandContext.Err() == context.DeadlineExceeded
+}
+
+// commandTimeoutError returns the error reported in place of whatever failed when the command timed out,
+// which is usually a killed Kubernetes CLI or a cancelled request that wouldn't explain itself.
+func commandTimeoutError() error {
+	return &timeoutError{fmt.Errorf("command timed out after %v", CommandTimeout)}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+var (
+	TridentConfigFile  string
+	TridentProfileName string
+)
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&TridentConfigFile, "trident-config", "",
+		"File of named profiles for connecting directly to the Trident REST interface")
+	RootCmd.PersistentFlags().StringVar(&TridentProfileName, "trident-profile", "",
+		"Profile to use from the --trident-config file")
+}
+
+// TridentConfig holds named connection profiles for the Trident REST interface, like a kubeconfig.
+// For example:
+//
+//	currentProfile: prod
+//	profiles:
+//	  prod:
+//	    server: https://trident.example.com:8443
+//	    token: <bearer token>
+//	    certificateAuthority: /etc/trident/ca.crt
+//	    namespace: trident
+type TridentConfig struct {
+	CurrentProfile string                    `json:"currentProfile,omitempty"`
+	Profiles       map[string]TridentProfile `json:"profiles"`
+}
+
+// TridentProfile is a self-contained connection profile.  Only the server is required.
+type TridentProfile struct {
+	Server               string `json:"server"`
+	Token                string `json:"token,omitempty"`
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+	Namespace            string `json:"namespace,omitempty"`
+}
+
+// readTridentProfile returns the selected profile from the Trident config file.  The profile is the one
+// named by --trident-profile, else the file's current profile, else the file's only profile.
+func readTridentProfile(path, name string) (*TridentProfile, error) {
+
+	configBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read Trident config %s; %v", path, err)
+	}
+
+	tridentConfig := &TridentConfig{}
+	if err = yaml.Unmarshal(configBytes, tridentConfig); err != nil {
+		return nil, fmt.Errorf("could not parse Trident config %s; %v", path, err)
+	}
+
+	if name == "" {
+		name = tridentConfig.CurrentProfile
+	}
+	if name == "" {
+		if len(tridentConfig.Profiles) != 1 {
+			return nil, fmt.Errorf("Trident config %s has %d profiles; specify one with --trident-profile",
+				path, len(tridentConfig.Profiles))
+		}
+		for profileName := range tridentConfig.Profiles {
+			name = profileName
+		}
+	}
+
+	profile, ok := tridentConfig.Profiles[name]
+	if !ok {
+		profileNames := make([]string, 0, len(tridentConfig.Profiles))
+		for profileName := range tridentConfig.Profiles {
+			profileNames = append(profileNames, profileName)
+		}
+		sort.Strings(profileNames)
+		return nil, fmt.Errorf("profile %s not found in Trident config %s; available profiles: %s",
+			name, path, strings.Join(profileNames, ", "))
+	}
+
+	if err = profile.validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile %s in Trident config %s; %v", name, path, err)
+	}
+
+	log.Debugf("Using profile %s from Trident config %s.", name, path)
+
+	return &profile, nil
+}
+
+func (p *TridentProfile) validate() error {
+	if p.Server == "" {
+		return errors.New("server must be specified")
+	}
+	return nil
+}
+
+// applyTridentProfile applies the client settings of a profile to the REST client.
+func applyTridentProfile(profile *TridentProfile) error {
+
+	if profile.Token != "" {
+		api.BearerToken = profile.Token
+	}
+	if profile.CertificateAuthority != "" {
+		return api.SetCertificateAuthority(profile.CertificateAuthority)
+	}
+	return nil
+}
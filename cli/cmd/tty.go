@@ -0,0 +1,61 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+var (
+	NoTTY   bool
+	NoColor bool
+
+	// useColor is set if output may be colored, which is decided once the flags are parsed
+	useColor bool
+)
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&NoTTY, "no-tty", false,
+		"Behave non-interactively even if attached to a terminal")
+	RootCmd.PersistentFlags().BoolVar(&NoColor, "no-color", false,
+		"Never color the output (env NO_COLOR)")
+	cobra.OnInitialize(initColor)
+}
+
+// initColor decides whether output may be colored.  Color is only for a person at a terminal, and
+// is disabled by --no-color or by setting NO_COLOR, as described at no-color.org.
+func initColor() {
+	useColor = !NoColor && os.Getenv("NO_COLOR") == "" && stdoutIsTerminal()
+}
+
+// colorize returns text in the specified color, or unchanged if output may not be colored.
+func colorize(color, text string) string {
+	if !useColor {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// isTerminal returns whether a file is an interactive terminal.  All interactive and cosmetic
+// behaviors should be decided by this, so that --no-tty disables them consistently.
+func isTerminal(file *os.File) bool {
+	return !NoTTY && terminal.IsTerminal(int(file.Fd()))
+}
+
+// stdoutIsTerminal returns whether results are written to an interactive terminal.  While the result
+// is captured, this is decided by the original stdout, so that capturing it for the audit log or the
+// clipboard doesn't change what is shown; a result written to an output file isn't shown at all.
+func stdoutIsTerminal() bool {
+	if OutputFile != "" {
+		return false
+	}
+	return isTerminal(resultStdout())
+}
@@ -30,9 +30,14 @@ func init() {
 }
 
 var uninstallCmd = &cobra.Command{
-	Use:   "uninstall",
-	Short: "Uninstall Trident",
+	Use:         "uninstall",
+	Short:       "Uninstall Trident",
+	Annotations: map[string]string{annotationMutates: "true"},
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if Explain {
+			explainCommand(cmd)
+			return
+		}
 		initInstallerLogging()
 		if err := discoverUninstallationEnvironment(); err != nil {
 			log.Fatalf("Uninstall pre-checks failed; %v", err)
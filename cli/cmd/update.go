@@ -2,17 +2,100 @@
 
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+const conflictRetryInterval = 500 * time.Millisecond
+
+var (
+	changesOnly        bool
+	retryOnConflict    bool
+	maxConflictRetries int
+)
 
 func init() {
 	RootCmd.AddCommand(updateCmd)
+	updateCmd.PersistentFlags().BoolVar(&changesOnly, "changes-only", false,
+		"Write only the fields changed by the update instead of the whole object")
+	updateCmd.PersistentFlags().BoolVar(&retryOnConflict, "retry-on-conflict", false,
+		"Re-fetch the object and re-apply the update if it conflicts with a concurrent update")
+	updateCmd.PersistentFlags().IntVar(&maxConflictRetries, "max-conflict-retries", 5,
+		"Maximum number of times to retry an update that conflicts, with --retry-on-conflict")
 }
 
 var updateCmd = &cobra.Command{
-	Use:   "update",
-	Short: "Modify a resource in Trident",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		err := discoverOperatingMode(cmd)
+	Use:         "update",
+	Short:       "Modify a resource in Trident",
+	Annotations: map[string]string{annotationNamespacedArgs: "true"},
+}
+
+// getUpdatePodFlags returns the update options to pass along to a tunneled update command.
+func getUpdatePodFlags() []podFlag {
+
+	var flags []podFlag
+	if retryOnConflict {
+		flags = append(flags, requiredPodFlag("--retry-on-conflict",
+			"--max-conflict-retries", strconv.Itoa(maxConflictRetries)))
+	}
+	return flags
+}
+
+// tunnelBackendUpdate runs a backend update in the Trident pod and writes the updated backend.  With
+// --changes-only, the backend is also read beforehand, and the changes are found here, since the
+// tridentctl in the pod may not support --changes-only.
+func tunnelBackendUpdate(command, backendNames []string) error {
+
+	var oldBackends, backends api.MultipleBackendResponse
+	if changesOnly && len(backendNames) == 1 {
+		if err := TunnelCommandJSON([]string{"get", "backend", backendNames[0]}, &oldBackends); err != nil {
+			return err
+		}
+	}
+
+	if err := TunnelCommandJSON(append(command, backendNames...), &backends, getUpdatePodFlags()...); err != nil {
 		return err
-	},
+	}
+
+	if changesOnly && len(oldBackends.Items) == 1 && len(backends.Items) == 1 {
+		return writeBackendChanges(oldBackends.Items[0], backends.Items[0])
+	}
+
+	WriteBackends(backends.Items)
+	return nil
+}
+
+// invokeUpdateRESTAPI sends an update request to Trident.  If the update conflicts with a concurrent
+// update and --retry-on-conflict was specified, the object is re-fetched and the update re-applied, up
+// to the maximum number of retries.
+func invokeUpdateRESTAPI(
+	method, url string, requestBody []byte, refetch func() error,
+) (*http.Response, []byte, error) {
+
+	for retries := 0; ; retries++ {
+
+		response, responseBody, err := api.InvokeRESTAPI(method, url, requestBody, Debug)
+		if err != nil || response.StatusCode != http.StatusConflict || !retryOnConflict ||
+			retries >= maxConflictRetries {
+
+			if retries > 0 {
+				log.Debugf("Update was retried %d times after conflicts.", retries)
+			}
+			return response, responseBody, err
+		}
+
+		log.Debugf("Update conflicted, retrying (%d of %d).", retries+1, maxConflictRetries)
+		time.Sleep(conflictRetryInterval)
+
+		if err = refetch(); err != nil {
+			return nil, nil, err
+		}
+	}
 }
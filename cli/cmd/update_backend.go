@@ -11,22 +11,22 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/storage"
 )
 
 func init() {
 	updateCmd.AddCommand(updateBackendCmd)
-	updateBackendCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path to YAML or JSON file")
+	updateBackendCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path or http, https, or file URL of YAML or JSON file")
 	updateBackendCmd.Flags().StringVarP(&b64Data, "base64", "", "", "Base64 encoding")
 	updateBackendCmd.Flags().MarkHidden("base64")
 }
 
 var updateBackendCmd = &cobra.Command{
-	Use:     "backend <name>",
-	Short:   "Update a backend in Trident",
-	Aliases: []string{"b"},
+	Use:         "backend <name>",
+	Short:       "Update a backend in Trident",
+	Aliases:     []string{"b"},
+	Annotations: map[string]string{annotationREST: "POST /backend/{name}"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		jsonData, err := getBackendData()
@@ -39,8 +39,7 @@ var updateBackendCmd = &cobra.Command{
 				"update", "backend",
 				"--base64", base64.StdEncoding.EncodeToString(jsonData),
 			}
-			TunnelCommand(append(command, args...))
-			return nil
+			return tunnelBackendUpdate(command, args)
 		} else {
 			return backendUpdate(args, jsonData)
 		}
@@ -63,10 +62,24 @@ func backendUpdate(backendNames []string, postData []byte) error {
 		return err
 	}
 
+	// Retrieve the backend before updating it so the changes may be reported
+	var oldBackend storage.BackendExternal
+	if changesOnly {
+		if oldBackend, err = GetBackend(baseURL, backendNames[0]); err != nil {
+			return err
+		}
+	}
+
 	// Send the file to Trident
 	url := baseURL + "/backend/" + backendNames[0]
 
-	response, responseBody, err := api.InvokeRESTAPI("POST", url, postData, Debug)
+	// Re-fetching the backend before retrying a conflict also keeps the reported changes accurate
+	refetch := func() (err error) {
+		oldBackend, err = GetBackend(baseURL, backendNames[0])
+		return err
+	}
+
+	response, responseBody, err := invokeUpdateRESTAPI("POST", url, postData, refetch)
 	if err != nil {
 		return err
 	} else if response.StatusCode != http.StatusOK {
@@ -90,7 +103,24 @@ func backendUpdate(backendNames []string, postData []byte) error {
 	}
 	backends = append(backends, backend)
 
+	if changesOnly {
+		return writeBackendChanges(oldBackend, backend)
+	}
+
 	WriteBackends(backends)
 
 	return nil
 }
+
+// writeBackendChanges writes the fields that differ between two versions of a backend.
+func writeBackendChanges(oldBackend, newBackend storage.BackendExternal) error {
+
+	changes, err := diffObjects(oldBackend, newBackend)
+	if err != nil {
+		return err
+	}
+
+	WriteObjectChanges([]ObjectChanges{{Name: newBackend.Name, Changes: changes}})
+
+	return nil
+}
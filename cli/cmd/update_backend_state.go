@@ -11,7 +11,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/storage"
 )
@@ -24,10 +23,11 @@ func init() {
 }
 
 var updateBackendStateCmd = &cobra.Command{
-	Use:     "state <name> <state>",
-	Short:   "Update a backend's state in Trident",
-	Aliases: []string{"s"},
-	Hidden:  true,
+	Use:         "state <name> <state>",
+	Short:       "Update a backend's state in Trident",
+	Aliases:     []string{"s"},
+	Hidden:      true,
+	Annotations: map[string]string{annotationREST: "POST /backend/{name}/state"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		newBackendState, err := getBackendState()
@@ -39,8 +39,7 @@ var updateBackendStateCmd = &cobra.Command{
 			command := []string{
 				"update", "backend", "state", "--state", backendState,
 			}
-			TunnelCommand(append(command, args...))
-			return nil
+			return tunnelBackendUpdate(command, args)
 		} else {
 			return backendUpdateState(args, newBackendState)
 		}
@@ -73,6 +72,14 @@ func backendUpdateState(backendNames []string, backendState string) error {
 		return err
 	}
 
+	// Retrieve the backend before updating it so the changes may be reported
+	var oldBackend storage.BackendExternal
+	if changesOnly {
+		if oldBackend, err = GetBackend(baseURL, backendNames[0]); err != nil {
+			return err
+		}
+	}
+
 	// Send the new backend state to Trident
 	url := baseURL + "/backend/" + backendNames[0] + "/state"
 
@@ -84,7 +91,13 @@ func backendUpdateState(backendNames []string, backendState string) error {
 		return err
 	}
 
-	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	// Re-fetching the backend before retrying a conflict also keeps the reported changes accurate
+	refetch := func() (err error) {
+		oldBackend, err = GetBackend(baseURL, backendNames[0])
+		return err
+	}
+
+	response, responseBody, err := invokeUpdateRESTAPI("POST", url, requestBytes, refetch)
 	if err != nil {
 		return err
 	} else if response.StatusCode != http.StatusOK {
@@ -108,6 +121,10 @@ func backendUpdateState(backendNames []string, backendState string) error {
 	}
 	backends = append(backends, backend)
 
+	if changesOnly {
+		return writeBackendChanges(oldBackend, backend)
+	}
+
 	WriteBackends(backends)
 
 	return nil
@@ -0,0 +1,65 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var urlPortForward bool
+
+func init() {
+	RootCmd.AddCommand(urlCmd)
+	urlCmd.Flags().BoolVar(&urlPortForward, "port-forward", false,
+		"Also print the command that forwards the URL's port to the Trident pod")
+}
+
+var urlCmd = &cobra.Command{
+	Use:   "url",
+	Short: "Print the URL of the Trident REST interface, without calling it",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		baseURL, err := GetBaseURL()
+		if err != nil {
+			return err
+		}
+
+		tridentURL := TridentURL{URL: baseURL}
+
+		// Through the pod, the URL is only reachable once its port is forwarded to the pod
+		if urlPortForward && OperatingMode == ModeTunnel {
+			port := PodServerPort.String()
+			tridentURL.PortForward = quoteCommandArgs(append([]string{KubernetesCLI}, kubectlArgs([]string{
+				"port-forward", "pod/" + TridentPodName, "-n", TridentPodNamespace,
+				"--address", PodServerHost, port + ":" + port,
+			})...))
+		}
+
+		WriteTridentURL(tridentURL)
+		return nil
+	},
+}
+
+// TridentURL is the URL at which tridentctl reaches the Trident REST interface.
+type TridentURL struct {
+	URL         string `json:"url"`
+	PortForward string `json:"portForward,omitempty"`
+}
+
+func WriteTridentURL(tridentURL TridentURL) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(tridentURL)
+	case FormatJSONPath:
+		WriteJSONPath(tridentURL)
+	case FormatYAML:
+		WriteYAML(tridentURL)
+	default:
+		fmt.Println(tridentURL.URL)
+		if tridentURL.PortForward != "" {
+			fmt.Println(tridentURL.PortForward)
+		}
+	}
+}
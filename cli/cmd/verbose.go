@@ -0,0 +1,102 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+var (
+	VerboseErrors bool
+	PrintCommands bool
+)
+
+// shellSafeArgRegex matches arguments that needn't be quoted to be pasted into a shell
+var shellSafeArgRegex = regexp.MustCompile(`^[A-Za-z0-9_./:=,@%+-]+$`)
+
+// commandRecord describes an external command run by tridentctl.
+type commandRecord struct {
+	args     []string
+	stderr   string
+	exitCode int
+}
+
+var lastCommand *commandRecord
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&VerboseErrors, "verbose-errors", false,
+		"On failure, also print the command, URL, stderr, exit code, and operating mode involved")
+	RootCmd.PersistentFlags().BoolVar(&PrintCommands, "print-commands", false,
+		"Print each Kubernetes CLI command to stderr before running it")
+}
+
+// recordCommand remembers the most recent external command, so that its context may be
+// reported if tridentctl fails.
+func recordCommand(cmd *exec.Cmd, stderr []byte, err error) {
+	lastCommand = &commandRecord{
+		args:     cmd.Args,
+		stderr:   strings.TrimSpace(string(stderr)),
+		exitCode: GetExitCodeFromError(err),
+	}
+}
+
+// writeFailureContext writes the context of a failure to stderr if verbose errors were requested.
+func writeFailureContext() {
+
+	if !VerboseErrors {
+		return
+	}
+
+	fmt.Fprintln(diagnosticOutput, "Failure context:")
+	fmt.Fprintf(diagnosticOutput, "  Operating mode: %s\n", OperatingMode)
+	if Server != "" {
+		fmt.Fprintf(diagnosticOutput, "  Server: %s\n", Server)
+	}
+	if OperatingMode == ModeTunnel {
+		fmt.Fprintf(diagnosticOutput, "  Kubernetes CLI: %s\n", KubernetesCLI)
+		fmt.Fprintf(diagnosticOutput, "  Trident pod: %s/%s\n", TridentPodNamespace, TridentPodName)
+	}
+	if lastCommand != nil {
+		fmt.Fprintf(diagnosticOutput, "  Last command: %s\n", strings.Join(lastCommand.args, " "))
+		fmt.Fprintf(diagnosticOutput, "  Exit code: %d\n", lastCommand.exitCode)
+		if lastCommand.stderr != "" {
+			fmt.Fprintf(diagnosticOutput, "  Stderr: %s\n", lastCommand.stderr)
+		}
+	}
+	if request := api.LastRequest(); request != "" {
+		fmt.Fprintf(diagnosticOutput, "  Last request: %s\n", request)
+	}
+}
+
+// printCommand writes an external command to stderr if --print-commands was specified, quoted so that
+// it may be pasted into a shell to reproduce the invocation.
+func printCommand(cmd *exec.Cmd) {
+
+	if !PrintCommands {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "+ "+quoteCommandArgs(cmd.Args))
+}
+
+// quoteCommandArgs joins command arguments into a single line, quoting any that the shell would
+// otherwise split or interpret.
+func quoteCommandArgs(args []string) string {
+
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if shellSafeArgRegex.MatchString(arg) {
+			quoted = append(quoted, arg)
+		} else {
+			quoted = append(quoted, "'"+strings.Replace(arg, "'", `'\''`, -1)+"'")
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -6,13 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend/rest"
 	"github.com/netapp/trident/utils"
-	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -29,16 +28,20 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version of Trident",
 	Long:  "Print the version of the Trident storage orchestrator for Kubernetes",
-	PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
-		if !clientOnly {
-			err = discoverOperatingMode(cmd)
-		}
-		return err
+	Annotations: map[string]string{
+		annotationSkipDiscovery:     "client",
+		annotationDiscoveryOptional: "true",
+		annotationREST:              "GET /version",
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		if clientOnly {
 			writeVersion(getClientVersion())
+		} else if discoveryError != nil {
+
+			// Report the client version even if the server can't be found
+			writeVersion(getClientVersion())
+			return discoveryError
 		} else {
 
 			var serverVersion rest.GetVersionResponse
@@ -53,6 +56,7 @@ var versionCmd = &cobra.Command{
 			}
 
 			if err != nil {
+				writeVersion(getClientVersion())
 				return err
 			}
 
@@ -110,9 +114,7 @@ func getVersionFromTunnel() (rest.GetVersionResponse, error) {
 		return rest.GetVersionResponse{}, err
 	}
 
-	if Debug {
-		fmt.Printf("Version JSON: %s\n", versionJSON)
-	}
+	log.Debugf("Version JSON: %s", versionJSON)
 
 	var tunnelVersionResponse api.VersionResponse
 	err = json.Unmarshal(versionJSON, &tunnelVersionResponse)
@@ -162,6 +164,8 @@ func writeVersion(version *api.ClientVersionResponse) {
 	switch OutputFormat {
 	case FormatJSON:
 		WriteJSON(version)
+	case FormatJSONPath:
+		WriteJSONPath(version)
 	case FormatYAML:
 		WriteYAML(version)
 	case FormatWide:
@@ -175,6 +179,8 @@ func writeVersions(versions *api.VersionResponse) {
 	switch OutputFormat {
 	case FormatJSON:
 		WriteJSON(versions)
+	case FormatJSONPath:
+		WriteJSONPath(versions)
 	case FormatYAML:
 		WriteYAML(versions)
 	case FormatWide:
@@ -186,7 +192,7 @@ func writeVersions(versions *api.VersionResponse) {
 
 func writeVersionTable(version *api.ClientVersionResponse) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Client Version"})
 
 	table.Append([]string{
@@ -198,7 +204,7 @@ func writeVersionTable(version *api.ClientVersionResponse) {
 
 func writeVersionsTable(versions *api.VersionResponse) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Server Version", "Client Version"})
 
 	table.Append([]string{
@@ -211,7 +217,7 @@ func writeVersionsTable(versions *api.VersionResponse) {
 
 func writeWideVersionTable(version *api.ClientVersionResponse) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Client Version", "Client API Version"})
 
 	table.Append([]string{
@@ -224,7 +230,7 @@ func writeWideVersionTable(version *api.ClientVersionResponse) {
 
 func writeWideVersionsTable(versions *api.VersionResponse) {
 
-	table := tablewriter.NewWriter(os.Stdout)
+	table := newTableWriter()
 	table.SetHeader([]string{"Server Version", "Server API Version", "Client Version", "Client API Version"})
 
 	table.Append([]string{
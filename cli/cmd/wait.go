@@ -0,0 +1,177 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+const (
+	waitForDelete      = "delete"
+	waitInterval       = 2 * time.Second
+	defaultWaitTimeout = 30 * time.Second
+)
+
+var waitFor string
+
+// waitKind describes where a kind of Trident resource is found in the REST API.
+type waitKind struct {
+	path        string
+	responseKey string
+}
+
+var waitKinds = map[string]waitKind{
+	"backend":      {path: "backend", responseKey: "backend"},
+	"volume":       {path: "volume", responseKey: "volume"},
+	"storageclass": {path: "storageclass", responseKey: "storageClass"},
+}
+
+var waitKindAliases = map[string]string{
+	"b":              "backend",
+	"backends":       "backend",
+	"v":              "volume",
+	"volumes":        "volume",
+	"sc":             "storageclass",
+	"storageclasses": "storageclass",
+}
+
+func init() {
+	RootCmd.AddCommand(waitCmd)
+	waitCmd.Flags().StringVar(&waitFor, "for", "",
+		"Condition to wait for: delete, or <field>=<value> (e.g. state=online)")
+}
+
+var waitCmd = &cobra.Command{
+	Use:   "wait <kind>/<name>",
+	Short: "Wait for a resource in Trident to reach a desired state",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"wait", args[0], "--for", waitFor}
+			var podFlags []podFlag
+			if CommandTimeout > 0 {
+				podFlags = append(podFlags, requiredPodFlag("--timeout", CommandTimeout.String()))
+			}
+			TunnelCommand(command, podFlags...)
+			return nil
+		} else {
+			return waitForResource(args[0])
+		}
+	},
+}
+
+func waitForResource(reference string) error {
+
+	kindName, name, err := parseResourceReference(reference)
+	if err != nil {
+		return err
+	}
+	kind := waitKinds[kindName]
+
+	var field, value string
+	if waitFor == "" {
+		return errors.New("no condition was specified with --for")
+	} else if waitFor != waitForDelete {
+		parts := strings.SplitN(waitFor, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("invalid condition %s; expected delete or <field>=<value>", waitFor)
+		}
+		field, value = parts[0], parts[1]
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+	url := baseURL + "/" + kind.path + "/" + name
+
+	// The wait is bounded by --timeout, like the rest of the command, or by a default if there is none
+	waitTimeout := CommandTimeout
+	if waitTimeout <= 0 {
+		waitTimeout = defaultWaitTimeout
+	}
+	deadline := time.Now().Add(waitTimeout)
+
+	for {
+		object, found, err := getWaitObject(url, kind)
+		if err != nil {
+			return err
+		}
+
+		if waitFor == waitForDelete {
+			if !found {
+				fmt.Printf("%s/%s deleted\n", kindName, name)
+				return nil
+			}
+		} else if found {
+			if fieldValue, err := getFieldValue(object, field); err == nil &&
+				strings.EqualFold(fmt.Sprintf("%v", fieldValue), value) {
+				fmt.Printf("%s/%s condition met\n", kindName, name)
+				return nil
+			}
+		}
+
+		// Check once more at the deadline before giving up
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return &timeoutError{fmt.Errorf("timed out waiting for the condition on %s/%s after %v",
+				kindName, name, waitTimeout)}
+		}
+		if remaining > waitInterval {
+			remaining = waitInterval
+		}
+
+		log.Debugf("Condition not yet met for %s/%s, waiting.", kindName, name)
+		time.Sleep(remaining)
+	}
+}
+
+// parseResourceReference splits a <kind>/<name> reference, resolving any kind alias.
+func parseResourceReference(reference string) (string, string, error) {
+
+	parts := strings.SplitN(reference, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid resource %s; expected <kind>/<name>", reference)
+	}
+
+	kind := strings.ToLower(parts[0])
+	if alias, ok := waitKindAliases[kind]; ok {
+		kind = alias
+	}
+	if _, ok := waitKinds[kind]; !ok {
+		return "", "", fmt.Errorf("unknown resource kind %s; expected backend, volume, or storageclass", parts[0])
+	}
+
+	return kind, parts[1], nil
+}
+
+// getWaitObject retrieves a resource, returning whether it exists.
+func getWaitObject(url string, kind waitKind) (interface{}, bool, error) {
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, false, err
+	} else if response.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	} else if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("could not get %s: %v", kind.path,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getResponse map[string]interface{}
+	if err = json.Unmarshal(responseBody, &getResponse); err != nil {
+		return nil, false, err
+	}
+
+	return getResponse[kind.responseKey], true, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	k8s "k8s.io/api/core/v1"
+)
+
+const (
+	WorkloadAuto       = "auto"
+	WorkloadDeployment = "deployment"
+	WorkloadDaemonSet  = "daemonset"
+
+	ownerKindDaemonSet = "DaemonSet"
+)
+
+var Workload string
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&Workload, "workload", WorkloadAuto,
+		"Trident workload whose pod is used. One of deployment (controller)|daemonset (node)|auto (by label only)")
+}
+
+// validateWorkload checks the value of the --workload flag.
+func validateWorkload() error {
+	switch Workload {
+	case WorkloadAuto, WorkloadDeployment, WorkloadDaemonSet:
+		return nil
+	default:
+		return fmt.Errorf("invalid workload %s; expected deployment, daemonset, or auto", Workload)
+	}
+}
+
+// getTridentWorkloadPod returns a Trident pod belonging to the requested workload.  Pods are found by
+// label and then filtered by the kind of their controlling owner, so that controller and node pods
+// aren't confused in setups where their labels overlap.  Any node pod will do, so the first one by
+// name is chosen.
+func getTridentWorkloadPod(namespace string) (*k8s.Pod, error) {
+
+	var appLabels []string
+	if Workload == WorkloadDaemonSet {
+		appLabels = []string{TridentNodeLabel}
+	} else if CSI {
+		appLabels = []string{TridentCSILabel}
+	} else {
+		appLabels = []string{TridentLabel, TridentCSILabel}
+	}
+
+	for _, appLabel := range appLabels {
+
+		pods, err := listRunningPods(namespace, appLabel)
+		if err != nil {
+			return nil, err
+		}
+
+		var workloadPods []k8s.Pod
+		for _, pod := range pods.Items {
+			if isDaemonSetPod(pod) == (Workload == WorkloadDaemonSet) {
+				workloadPods = append(workloadPods, pod)
+			}
+		}
+
+		if Workload == WorkloadDaemonSet && len(workloadPods) > 0 {
+			sort.Slice(workloadPods, func(i, j int) bool { return workloadPods[i].Name < workloadPods[j].Name })
+			if len(workloadPods) > 1 {
+				log.Debugf("Found %d Trident node pods, using %s.", len(workloadPods), workloadPods[0].Name)
+			}
+			return &workloadPods[0], nil
+		} else if len(workloadPods) == 1 {
+			return &workloadPods[0], nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find a Trident %s pod in the %s namespace. "+
+		"You may need to use the -n option to specify the correct namespace", Workload, namespace)
+}
+
+// isDaemonSetPod returns whether a pod is controlled by a DaemonSet.
+func isDaemonSetPod(pod k8s.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == ownerKindDaemonSet {
+			return true
+		}
+	}
+	return false
+}
@@ -3,8 +3,6 @@
 package main
 
 import (
-	"os"
-
 	"github.com/netapp/trident/cli/cmd"
 )
 
@@ -12,8 +10,9 @@ func main() {
 	cmd.ExitCode = cmd.ExitCodeSuccess
 
 	if err := cmd.RootCmd.Execute(); err != nil {
+		cmd.WriteError(err)
 		cmd.SetExitCodeFromError(err)
 	}
 
-	os.Exit(cmd.ExitCode)
+	cmd.Exit(cmd.ExitCode)
 }